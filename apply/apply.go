@@ -0,0 +1,95 @@
+// Package apply applies Rollouts with server-side apply, using the generated apply configurations of
+// applyconfiguration/v1alpha1. It is kept apart from v1alpha1 so that the API types do not depend on client-go.
+//
+// The apply configurations are generated without an OpenAPI model of the Rollout types, so extracting the
+// configuration owned by a field manager from a Rollout (the Extract functions of client-go) is unsupported.
+package apply
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	applyv1alpha1 "github.com/chamhaw/kubernetes-rollout-api/applyconfiguration/v1alpha1"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+// RolloutApplier applies Rollouts with server-side apply. The RolloutInterface of the clients of this
+// module (the fake Clientset and the kubeconfig client of the CLI) also implement RolloutApplier.
+type RolloutApplier interface {
+	// Apply applies the configuration with server-side apply, opts.FieldManager is required.
+	Apply(ctx context.Context, clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration, opts metav1.ApplyOptions) (*v1alpha1.Rollout, error)
+	// ApplyStatus applies the status of the configuration to the status subresource with server-side apply,
+	// opts.FieldManager is required.
+	ApplyStatus(ctx context.Context, clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration, opts metav1.ApplyOptions) (*v1alpha1.Rollout, error)
+}
+
+// ForRollouts returns the RolloutApplier of a RolloutInterface, false if it does not support apply.
+func ForRollouts(rollouts v1alpha1.RolloutInterface) (RolloutApplier, bool) {
+	applier, ok := rollouts.(RolloutApplier)
+	return applier, ok
+}
+
+// NewInstanceIDApplier returns a RolloutApplier setting the instance ID label in the applied configurations,
+// so the field manager owns it, like v1alpha1.NewInstanceIDRolloutsGetter does for created Rollouts. The label
+// is removed from the configurations applied by the default instance.
+func NewInstanceIDApplier(applier RolloutApplier, instanceID string) RolloutApplier {
+	return &instanceIDApplier{applier: applier, instanceID: instanceID}
+}
+
+type instanceIDApplier struct {
+	applier    RolloutApplier
+	instanceID string
+}
+
+func (a *instanceIDApplier) Apply(ctx context.Context, clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration, opts metav1.ApplyOptions) (*v1alpha1.Rollout, error) {
+	return a.applier.Apply(ctx, clusterCode, a.withInstanceID(rollout), opts)
+}
+
+func (a *instanceIDApplier) ApplyStatus(ctx context.Context, clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration, opts metav1.ApplyOptions) (*v1alpha1.Rollout, error) {
+	return a.applier.ApplyStatus(ctx, clusterCode, rollout, opts)
+}
+
+// withInstanceID returns a copy of the configuration with the instance ID label, or without it for the default
+// instance, the configuration is not modified.
+func (a *instanceIDApplier) withInstanceID(rollout *applyv1alpha1.RolloutApplyConfiguration) *applyv1alpha1.RolloutApplyConfiguration {
+	if rollout == nil {
+		return rollout
+	}
+	if a.instanceID == "" {
+		if rollout.ObjectMetaApplyConfiguration == nil {
+			return rollout
+		}
+		if _, ok := rollout.Labels[v1alpha1.LabelKeyControllerInstanceID]; !ok {
+			return rollout
+		}
+	}
+	withLabel := *rollout
+	if rollout.ObjectMetaApplyConfiguration != nil {
+		meta := *rollout.ObjectMetaApplyConfiguration
+		withLabel.ObjectMetaApplyConfiguration = &meta
+		withLabel.Labels = make(map[string]string, len(rollout.Labels)+1)
+		for k, v := range rollout.Labels {
+			withLabel.Labels[k] = v
+		}
+	}
+	if a.instanceID == "" {
+		delete(withLabel.Labels, v1alpha1.LabelKeyControllerInstanceID)
+	} else {
+		withLabel.WithLabels(map[string]string{v1alpha1.LabelKeyControllerInstanceID: a.instanceID})
+	}
+	return &withLabel
+}
+
+// WithCanarySteps adds the steps to the canary strategy of the spec configuration, creating the strategy
+// if needed, and returns the spec. If called multiple times, the steps of each call are appended.
+func WithCanarySteps(spec *applyv1alpha1.RolloutSpecApplyConfiguration, steps ...*applyv1alpha1.CanaryStepApplyConfiguration) *applyv1alpha1.RolloutSpecApplyConfiguration {
+	if spec.Strategy == nil {
+		spec.WithStrategy(applyv1alpha1.RolloutStrategy())
+	}
+	if spec.Strategy.Canary == nil {
+		spec.Strategy.WithCanary(applyv1alpha1.CanaryStrategy())
+	}
+	spec.Strategy.Canary.WithSteps(steps...)
+	return spec
+}
@@ -0,0 +1,161 @@
+package apply_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"github.com/chamhaw/kubernetes-rollout-api/apply"
+	applyv1alpha1 "github.com/chamhaw/kubernetes-rollout-api/applyconfiguration/v1alpha1"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1/fake"
+)
+
+func TestRolloutApplyConfiguration(t *testing.T) {
+	config := applyv1alpha1.Rollout("web", "default").
+		WithLabels(map[string]string{"app": "web"}).
+		WithSpec(apply.WithCanarySteps(applyv1alpha1.RolloutSpec().WithReplicas(3),
+			applyv1alpha1.CanaryStep().WithSetWeight(20),
+			applyv1alpha1.CanaryStep().WithPause(applyv1alpha1.RolloutPause().WithDuration(intstr.FromString("1m"))),
+		))
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"kind":"Rollout","apiVersion":"argoproj.io/v1alpha1","metadata":{"name":"web","namespace":"default","labels":{"app":"web"}},` +
+		`"spec":{"replicas":3,"strategy":{"canary":{"steps":[{"setWeight":20},{"pause":{"duration":"1m"}}]}}}}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	var ro v1alpha1.Rollout
+	if err := json.Unmarshal(data, &ro); err != nil {
+		t.Fatal(err)
+	}
+	if *ro.Spec.Replicas != 3 || len(ro.Spec.Strategy.Canary.Steps) != 2 || ro.Spec.Strategy.Canary.Steps[1].Pause.DurationSeconds() != 60 {
+		t.Errorf("unexpected rollout %v", ro.Spec)
+	}
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset("dev")
+	rollouts, ok := apply.ForRollouts(client.Rollouts("default"))
+	if !ok {
+		t.Fatal("expected the fake rollouts to support apply")
+	}
+	opts := metav1.ApplyOptions{FieldManager: "test"}
+
+	if _, err := rollouts.Apply(ctx, "dev", applyv1alpha1.Rollout("web", "default"), metav1.ApplyOptions{}); !errors.IsBadRequest(err) {
+		t.Errorf("expected BadRequest without a field manager, got %v", err)
+	}
+	if _, err := rollouts.ApplyStatus(ctx, "dev", applyv1alpha1.Rollout("web", "default"), opts); !errors.IsNotFound(err) {
+		t.Errorf("expected NotFound applying the status of a missing rollout, got %v", err)
+	}
+
+	template := corev1apply.PodTemplateSpec().WithSpec(corev1apply.PodSpec().
+		WithContainers(corev1apply.Container().WithName("main").WithImage("nginx:1.23")))
+	created, err := rollouts.Apply(ctx, "dev", applyv1alpha1.Rollout("web", "default").
+		WithSpec(apply.WithCanarySteps(applyv1alpha1.RolloutSpec().WithReplicas(2).WithTemplate(template),
+			applyv1alpha1.CanaryStep().WithSetWeight(50))), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *created.Spec.Replicas != 2 || created.Generation != 1 || created.ResourceVersion == "" {
+		t.Errorf("unexpected created rollout %v", created)
+	}
+
+	unchanged, err := rollouts.Apply(ctx, "dev", applyv1alpha1.Rollout("web", "default").
+		WithSpec(applyv1alpha1.RolloutSpec().WithReplicas(2)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unchanged.ResourceVersion != created.ResourceVersion {
+		t.Errorf("expected a no-op apply to keep resource version %s, got %s", created.ResourceVersion, unchanged.ResourceVersion)
+	}
+
+	updated, err := rollouts.Apply(ctx, "dev", applyv1alpha1.Rollout("web", "default").
+		WithLabels(map[string]string{"app": "web"}).
+		WithSpec(applyv1alpha1.RolloutSpec().WithReplicas(5)).
+		WithStatus(applyv1alpha1.RolloutStatus().WithAbort(true)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *updated.Spec.Replicas != 5 || updated.Generation != 2 || updated.Labels["app"] != "web" {
+		t.Errorf("unexpected updated rollout %v", updated)
+	}
+	if updated.Spec.Template.Spec.Containers[0].Image != "nginx:1.23" || len(updated.Spec.Strategy.Canary.Steps) != 1 {
+		t.Errorf("expected fields absent from the configuration to be kept, got %v", updated.Spec)
+	}
+	if updated.Status.Abort {
+		t.Errorf("expected Apply to ignore the status")
+	}
+
+	status, err := rollouts.ApplyStatus(ctx, "dev", applyv1alpha1.Rollout("web", "default").
+		WithSpec(applyv1alpha1.RolloutSpec().WithReplicas(1)).
+		WithStatus(applyv1alpha1.RolloutStatus().WithPhase(v1alpha1.RolloutPhaseHealthy).WithObservedGeneration(2)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status.Phase != v1alpha1.RolloutPhaseHealthy || status.Status.ObservedGeneration != 2 || *status.Spec.Replicas != 5 {
+		t.Errorf("expected ApplyStatus to only change the status, got %v", status)
+	}
+
+	instance := apply.NewInstanceIDApplier(rollouts, "other")
+	config := applyv1alpha1.Rollout("api", "default").WithLabels(map[string]string{"app": "api"})
+	applied, err := instance.Apply(ctx, "dev", config, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied.Labels[v1alpha1.LabelKeyControllerInstanceID] != "other" || applied.Labels["app"] != "api" {
+		t.Errorf("applied rollout should be stamped with the instance ID, got labels %v", applied.Labels)
+	}
+	if _, ok := config.Labels[v1alpha1.LabelKeyControllerInstanceID]; ok {
+		t.Errorf("the configuration passed to Apply should not be modified")
+	}
+
+	defaultInstance := apply.NewInstanceIDApplier(rollouts, "")
+	config = applyv1alpha1.Rollout("worker", "default").
+		WithLabels(map[string]string{"app": "worker", v1alpha1.LabelKeyControllerInstanceID: "other"})
+	applied, err = defaultInstance.Apply(ctx, "dev", config, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := applied.Labels[v1alpha1.LabelKeyControllerInstanceID]; ok || applied.Labels["app"] != "worker" {
+		t.Errorf("rollout applied by the default instance should not have the instance ID, got labels %v", applied.Labels)
+	}
+	if config.Labels[v1alpha1.LabelKeyControllerInstanceID] != "other" {
+		t.Errorf("the configuration passed to Apply should not be modified")
+	}
+}
+
+// The fake applies the configuration as a strategic merge patch without tracking the managed fields: unlike
+// server-side apply, a field removed from the configuration of a field manager is kept.
+func TestFakeApplyKeepsRemovedFields(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset("dev")
+	rollouts, _ := apply.ForRollouts(client.Rollouts("default"))
+	opts := metav1.ApplyOptions{FieldManager: "test"}
+
+	if _, err := rollouts.Apply(ctx, "dev", applyv1alpha1.Rollout("web", "default").
+		WithLabels(map[string]string{"app": "web", "tier": "frontend"}).
+		WithSpec(applyv1alpha1.RolloutSpec().WithReplicas(2).WithMinReadySeconds(10)), opts); err != nil {
+		t.Fatal(err)
+	}
+	applied, err := rollouts.Apply(ctx, "dev", applyv1alpha1.Rollout("web", "default").
+		WithLabels(map[string]string{"app": "web"}).
+		WithSpec(applyv1alpha1.RolloutSpec().WithReplicas(2)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a server would remove the label and the minReadySeconds no longer applied by the field manager
+	if applied.Labels["tier"] != "frontend" || applied.Spec.MinReadySeconds != 10 {
+		t.Errorf("expected the fake to keep the fields removed from the configuration, got labels %v and minReadySeconds %d",
+			applied.Labels, applied.Spec.MinReadySeconds)
+	}
+}
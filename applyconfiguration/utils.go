@@ -0,0 +1,45 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package applyconfiguration
+
+import (
+	kubernetesrolloutapiv1alpha1 "github.com/chamhaw/kubernetes-rollout-api/applyconfiguration/v1alpha1"
+	v1alpha1 "github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ForKind returns an apply configuration type for the given GroupVersionKind, or nil if no
+// apply configuration type exists for the given GroupVersionKind.
+func ForKind(kind schema.GroupVersionKind) interface{} {
+	switch kind {
+	// Group=argoproj.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("AnalysisRunStrategy"):
+		return &kubernetesrolloutapiv1alpha1.AnalysisRunStrategyApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("CanaryStep"):
+		return &kubernetesrolloutapiv1alpha1.CanaryStepApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("CanaryStrategy"):
+		return &kubernetesrolloutapiv1alpha1.CanaryStrategyApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ObjectRef"):
+		return &kubernetesrolloutapiv1alpha1.ObjectRefApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PauseCondition"):
+		return &kubernetesrolloutapiv1alpha1.PauseConditionApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PodTemplateMetadata"):
+		return &kubernetesrolloutapiv1alpha1.PodTemplateMetadataApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("Rollout"):
+		return &kubernetesrolloutapiv1alpha1.RolloutApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RolloutCondition"):
+		return &kubernetesrolloutapiv1alpha1.RolloutConditionApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RolloutPause"):
+		return &kubernetesrolloutapiv1alpha1.RolloutPauseApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RolloutSpec"):
+		return &kubernetesrolloutapiv1alpha1.RolloutSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RolloutStatus"):
+		return &kubernetesrolloutapiv1alpha1.RolloutStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RolloutStrategy"):
+		return &kubernetesrolloutapiv1alpha1.RolloutStrategyApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("SetCanaryScale"):
+		return &kubernetesrolloutapiv1alpha1.SetCanaryScaleApplyConfiguration{}
+
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+// newActionCommand returns a command applying mutate to the rollout given as argument.
+func newActionCommand(o *Options, use, short, done string, mutate func(ro *v1alpha1.Rollout) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " NAME",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := o.update(cmd.Context(), args[0], mutate); err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "rollout '%s' %s\n", args[0], done)
+			return nil
+		},
+	}
+}
+
+func newPromoteCommand(o *Options) *cobra.Command {
+	var full bool
+	cmd := &cobra.Command{
+		Use:   "promote NAME",
+		Short: "Promote a rollout to its next step",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := o.update(cmd.Context(), args[0], func(ro *v1alpha1.Rollout) error {
+				v1alpha1.Promote(ro, full)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if full {
+				fmt.Fprintf(o.Out, "rollout '%s' fully promoted\n", args[0])
+			} else {
+				fmt.Fprintf(o.Out, "rollout '%s' promoted\n", args[0])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&full, "full", false, "Skip all the remaining steps")
+	return cmd
+}
+
+func newPauseCommand(o *Options) *cobra.Command {
+	return newActionCommand(o, "pause", "Pause a rollout", "paused", func(ro *v1alpha1.Rollout) error {
+		v1alpha1.Pause(ro)
+		return nil
+	})
+}
+
+func newResumeCommand(o *Options) *cobra.Command {
+	return newActionCommand(o, "resume", "Resume a paused rollout", "resumed", func(ro *v1alpha1.Rollout) error {
+		v1alpha1.Resume(ro)
+		return nil
+	})
+}
+
+func newAbortCommand(o *Options) *cobra.Command {
+	return newActionCommand(o, "abort", "Abort a rollout and scale back to the stable ReplicaSet", "aborted", func(ro *v1alpha1.Rollout) error {
+		v1alpha1.Abort(ro)
+		return nil
+	})
+}
+
+func newRetryCommand(o *Options) *cobra.Command {
+	return newActionCommand(o, "retry", "Retry an aborted rollout", "retried", func(ro *v1alpha1.Rollout) error {
+		if !v1alpha1.IsAborted(ro) {
+			return fmt.Errorf("rollout '%s' is not aborted", ro.Name)
+		}
+		v1alpha1.Retry(ro)
+		return nil
+	})
+}
+
+func newRestartCommand(o *Options) *cobra.Command {
+	return newActionCommand(o, "restart", "Restart the pods of a rollout", "restarts", func(ro *v1alpha1.Rollout) error {
+		now := metav1.Now()
+		ro.Spec.RestartAt = &now
+		return nil
+	})
+}
+
+func newSetImageCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-image NAME CONTAINER=IMAGE...",
+		Short: "Update the image of containers of a rollout, use * as CONTAINER to update all of them",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			images := map[string]string{}
+			for _, arg := range args[1:] {
+				parts := strings.SplitN(arg, "=", 2)
+				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+					return fmt.Errorf("invalid argument %q, expected CONTAINER=IMAGE", arg)
+				}
+				images[parts[0]] = parts[1]
+			}
+			_, err := o.update(cmd.Context(), args[0], func(ro *v1alpha1.Rollout) error {
+				return setImages(ro, images)
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "rollout '%s' image updated\n", args[0])
+			return nil
+		},
+	}
+}
+
+func setImages(ro *v1alpha1.Rollout, images map[string]string) error {
+	found := map[string]bool{}
+	update := func(containers []corev1.Container) {
+		for i := range containers {
+			image, ok := images[containers[i].Name]
+			if !ok {
+				image, ok = images["*"]
+			}
+			if ok {
+				containers[i].Image = image
+				found[containers[i].Name] = true
+				found["*"] = true
+			}
+		}
+	}
+	update(ro.Spec.Template.Spec.InitContainers)
+	update(ro.Spec.Template.Spec.Containers)
+	for name := range images {
+		if !found[name] {
+			return fmt.Errorf("unable to find container named %q in rollout '%s'", name, ro.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Package cli implements the rollouts command-line tool on top of a v1alpha1.RolloutsGetter, so that it can
+// be embedded in other tools and tested against the in-memory fake client.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+const (
+	// OutputTable prints a human readable table
+	OutputTable = "table"
+	// OutputJSON prints the objects as JSON
+	OutputJSON = "json"
+	// OutputYAML prints the objects as YAML
+	OutputYAML = "yaml"
+)
+
+// Options holds the global flags and the dependencies of the commands.
+type Options struct {
+	// Client is used to get and update the rollouts
+	Client v1alpha1.RolloutsGetter
+	// Out and ErrOut are the standard and error outputs of the commands
+	Out    io.Writer
+	ErrOut io.Writer
+
+	// Cluster is the clusterCode passed to the client
+	Cluster string
+	// Namespace of the rollouts
+	Namespace string
+	// Output is the output format: table, json or yaml
+	Output string
+}
+
+// NewCommand returns the root command of the rollouts tool.
+func NewCommand(client v1alpha1.RolloutsGetter, out, errOut io.Writer) *cobra.Command {
+	o := &Options{Client: client, Out: out, ErrOut: errOut}
+	cmd := &cobra.Command{
+		Use:           "rollouts",
+		Short:         "Manage Rollouts",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			switch o.Output {
+			case OutputTable, OutputJSON, OutputYAML:
+				return nil
+			}
+			return fmt.Errorf("unknown output format %q, expected one of %s, %s, %s", o.Output, OutputTable, OutputJSON, OutputYAML)
+		},
+	}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.PersistentFlags().StringVar(&o.Cluster, "cluster", "", "Code of the cluster of the rollouts")
+	cmd.PersistentFlags().StringVarP(&o.Namespace, "namespace", "n", metav1.NamespaceDefault, "Namespace of the rollouts")
+	cmd.PersistentFlags().StringVarP(&o.Output, "output", "o", OutputTable, "Output format: table, json or yaml")
+
+	cmd.AddCommand(
+		newGetCommand(o),
+		newListCommand(o),
+		newStatusCommand(o),
+		newPromoteCommand(o),
+		newPauseCommand(o),
+		newResumeCommand(o),
+		newAbortCommand(o),
+		newRetryCommand(o),
+		newRestartCommand(o),
+		newSetImageCommand(o),
+		newLintCommand(o),
+		newDiffCommand(o),
+	)
+	return cmd
+}
+
+func (o *Options) rollouts() v1alpha1.RolloutInterface {
+	return o.Client.Rollouts(o.Namespace)
+}
+
+// listWatcher returns the RolloutListWatcher of the client, an error if it can not list and watch.
+func (o *Options) listWatcher() (v1alpha1.RolloutListWatcher, error) {
+	listWatcher, ok := v1alpha1.ListWatcherFor(o.rollouts())
+	if !ok {
+		return nil, fmt.Errorf("the client can not list and watch rollouts")
+	}
+	return listWatcher, nil
+}
+
+func (o *Options) get(ctx context.Context, name string) (*v1alpha1.Rollout, error) {
+	return o.rollouts().Get(ctx, o.Cluster, o.Namespace, name)
+}
+
+// update applies mutate to the rollout, then persists the spec and the status changes.
+func (o *Options) update(ctx context.Context, name string, mutate func(rollout *v1alpha1.Rollout) error) (*v1alpha1.Rollout, error) {
+	ro, err := o.get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	updated := ro.DeepCopy()
+	if err := mutate(updated); err != nil {
+		return nil, err
+	}
+
+	result := ro
+	if !equality.Semantic.DeepEqual(ro.ObjectMeta, updated.ObjectMeta) || !equality.Semantic.DeepEqual(ro.Spec, updated.Spec) {
+		result, err = o.rollouts().Update(ctx, o.Cluster, updated)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !equality.Semantic.DeepEqual(ro.Status, updated.Status) {
+		withStatus := result.DeepCopy()
+		updated.Status.DeepCopyInto(&withStatus.Status)
+		result, err = o.rollouts().UpdateStatus(ctx, o.Cluster, withStatus)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
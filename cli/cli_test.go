@@ -0,0 +1,331 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/chamhaw/kubernetes-rollout-api/diff"
+	"github.com/chamhaw/kubernetes-rollout-api/lint"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1/fake"
+)
+
+func newTestRollout(name string) *v1alpha1.Rollout {
+	weight := int32(20)
+	index := int32(1)
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps", Generation: 1},
+		Spec: v1alpha1.RolloutSpec{
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: "nginx:1.23"}, {Name: "sidecar", Image: "envoy:1.0"}},
+			}},
+			Strategy: v1alpha1.RolloutStrategy{Canary: &v1alpha1.CanaryStrategy{
+				Steps: []v1alpha1.CanaryStep{{SetWeight: &weight}, {Pause: &v1alpha1.RolloutPause{}}, {SetWeight: &weight}},
+			}},
+		},
+		Status: v1alpha1.RolloutStatus{
+			CurrentStepIndex:   &index,
+			ObservedGeneration: 1,
+			Phase:              v1alpha1.RolloutPhasePaused,
+			PauseConditions:    []v1alpha1.PauseCondition{{Reason: v1alpha1.PauseReasonCanaryPauseStep, StartTime: metav1.Now()}},
+		},
+	}
+}
+
+func run(t *testing.T, client v1alpha1.RolloutsGetter, args ...string) (string, error) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	cmd := NewCommand(client, &out, &errOut)
+	cmd.SetArgs(append([]string{"--cluster", "dev", "-n", "apps"}, args...))
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestListAndGet(t *testing.T) {
+	client := fake.NewClientset("dev", newTestRollout("web"), newTestRollout("api"))
+
+	out, err := run(t, client, "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "api ") || !strings.Contains(lines[2], "Paused") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+
+	out, err = run(t, client, "get", "web", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ro v1alpha1.Rollout
+	if err := json.Unmarshal([]byte(out), &ro); err != nil || ro.Name != "web" {
+		t.Errorf("unexpected json output %s: %v", out, err)
+	}
+
+	out, err = run(t, client, "list", "-o", "yaml")
+	if err != nil || !strings.Contains(out, "kind: RolloutList") {
+		t.Errorf("unexpected yaml output %s: %v", out, err)
+	}
+	if _, err := run(t, client, "list", "-o", "xml"); err == nil {
+		t.Errorf("expected an error for an unknown output format")
+	}
+	if _, err := run(t, client, "get", "missing"); err == nil {
+		t.Errorf("expected an error for a missing rollout")
+	}
+}
+
+func TestActions(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset("dev", newTestRollout("web"))
+	get := func() *v1alpha1.Rollout {
+		ro, err := client.Rollouts("apps").Get(ctx, "dev", "apps", "web")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ro
+	}
+
+	if _, err := run(t, client, "promote", "web"); err != nil {
+		t.Fatal(err)
+	}
+	if ro := get(); len(ro.Status.PauseConditions) != 0 || *ro.Status.CurrentStepIndex != 1 {
+		t.Errorf("expected the pause step to be resumed, got %v", ro.Status)
+	}
+
+	if _, err := run(t, client, "pause", "web"); err != nil || !get().Spec.Paused {
+		t.Errorf("expected the rollout to be paused: %v", err)
+	}
+	if _, err := run(t, client, "resume", "web"); err != nil || get().Spec.Paused {
+		t.Errorf("expected the rollout to be resumed: %v", err)
+	}
+
+	if _, err := run(t, client, "retry", "web"); err == nil {
+		t.Errorf("expected retry to fail on a rollout which is not aborted")
+	}
+	if _, err := run(t, client, "abort", "web"); err != nil || !get().Status.Abort {
+		t.Errorf("expected the rollout to be aborted: %v", err)
+	}
+	if _, err := run(t, client, "retry", "web"); err != nil || get().Status.Abort {
+		t.Errorf("expected the rollout to be retried: %v", err)
+	}
+
+	if _, err := run(t, client, "promote", "web", "--full"); err != nil {
+		t.Fatal(err)
+	}
+	if ro := get(); *ro.Status.CurrentStepIndex != 3 {
+		t.Errorf("expected a full promotion, got step %d", *ro.Status.CurrentStepIndex)
+	}
+
+	if _, err := run(t, client, "restart", "web"); err != nil || get().Spec.RestartAt == nil {
+		t.Errorf("expected restartAt to be set: %v", err)
+	}
+
+	if _, err := run(t, client, "set-image", "web", "main=nginx:1.24"); err != nil {
+		t.Fatal(err)
+	}
+	ro := get()
+	if ro.Spec.Template.Spec.Containers[0].Image != "nginx:1.24" || ro.Spec.Template.Spec.Containers[1].Image != "envoy:1.0" {
+		t.Errorf("unexpected containers %v", ro.Spec.Template.Spec.Containers)
+	}
+	if ro.Generation != 5 {
+		t.Errorf("expected generation 5 after four spec changes, got %d", ro.Generation)
+	}
+	if _, err := run(t, client, "set-image", "web", "missing=nginx"); err == nil {
+		t.Errorf("expected an error for an unknown container")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestStatusWatch(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset("dev", newTestRollout("web"))
+
+	out := &syncBuffer{}
+	cmd := NewCommand(client, out, out)
+	cmd.SetArgs([]string{"--cluster", "dev", "-n", "apps", "status", "web", "--watch", "--timeout", "5s"})
+	done := make(chan error)
+	go func() {
+		done <- cmd.ExecuteContext(ctx)
+	}()
+
+	rollouts := client.Rollouts("apps")
+	for i, phase := range []v1alpha1.RolloutPhase{v1alpha1.RolloutPhaseProgressing, v1alpha1.RolloutPhaseDegraded} {
+		// wait for the previous status to be printed, the command is then watching
+		for strings.Count(out.String(), "\n") <= i {
+			time.Sleep(10 * time.Millisecond)
+		}
+		ro, err := rollouts.Get(ctx, "dev", "apps", "web")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ro.Status.Phase = phase
+		ro.Status.Message = string(phase) + " message"
+		if _, err := rollouts.UpdateStatus(ctx, "dev", ro); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := <-done
+	if err == nil || !strings.Contains(err.Error(), "degraded") {
+		t.Errorf("expected an error for a degraded rollout, got %v", err)
+	}
+	expected := "Paused\nProgressing - Progressing message\nDegraded - Degraded message\n"
+	if out.String() != expected {
+		t.Errorf("expected output %q, got %q", expected, out.String())
+	}
+}
+
+// watchRollouts replaces the watches of a RolloutInterface by the queued watchers, recording their options
+type watchRollouts struct {
+	v1alpha1.RolloutInterface
+	watchers []*watch.FakeWatcher
+	opts     []metav1.ListOptions
+}
+
+func (r *watchRollouts) Rollouts(namespace string) v1alpha1.RolloutInterface {
+	return r
+}
+
+func (r *watchRollouts) List(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (*v1alpha1.RolloutList, error) {
+	listWatcher, _ := v1alpha1.ListWatcherFor(r.RolloutInterface)
+	return listWatcher.List(ctx, clusterCode, namespace, opts)
+}
+
+func (r *watchRollouts) Watch(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	r.opts = append(r.opts, opts)
+	w := r.watchers[0]
+	r.watchers = r.watchers[1:]
+	return w, nil
+}
+
+func TestStatusWatchReconnect(t *testing.T) {
+	ro := newTestRollout("web")
+	client := fake.NewClientset("dev", ro)
+	created, err := client.Rollouts("apps").Get(context.Background(), "dev", "apps", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closed := watch.NewFake()
+	closed.Stop()
+	progressing := created.DeepCopy()
+	progressing.ResourceVersion = "42"
+	progressing.Status.Phase = v1alpha1.RolloutPhaseProgressing
+	reconnected := watch.NewFakeWithChanSize(2, false)
+	reconnected.Modify(progressing)
+	reconnected.Error(&metav1.Status{Status: metav1.StatusFailure, Code: 410, Reason: metav1.StatusReasonExpired,
+		Message: "too old resource version"})
+	rollouts := &watchRollouts{RolloutInterface: client.Rollouts("apps"), watchers: []*watch.FakeWatcher{closed, reconnected}}
+
+	out := &syncBuffer{}
+	cmd := NewCommand(rollouts, out, out)
+	cmd.SetArgs([]string{"--cluster", "dev", "-n", "apps", "status", "web", "--watch", "--timeout", "5s"})
+	err = cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "too old resource version") {
+		t.Errorf("expected the watch error to be returned, got %v", err)
+	}
+	if out.String() != "Paused\nProgressing\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	if len(rollouts.opts) != 2 {
+		t.Fatalf("expected the closed watch to be re-established, got %d watches", len(rollouts.opts))
+	}
+	for i, opts := range rollouts.opts {
+		if opts.FieldSelector != "metadata.name=web" || opts.ResourceVersion != created.ResourceVersion {
+			t.Errorf("unexpected options of watch %d: %+v", i, opts)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	client := fake.NewClientset("dev")
+	out, err := run(t, client, "lint", "../lint/testdata/rollouts.yaml", "-o", "json")
+	if err == nil {
+		t.Errorf("expected an error for a file with lint errors")
+	}
+	var problems []lint.Problem
+	if err := json.Unmarshal([]byte(out), &problems); err != nil || len(problems) != 5 {
+		t.Errorf("unexpected json output %s: %v", out, err)
+	}
+
+	var stdout bytes.Buffer
+	cmd := NewCommand(client, &stdout, &stdout)
+	cmd.SetIn(strings.NewReader("apiVersion: argoproj.io/v1alpha1\nkind: Rollout\nmetadata:\n  name: web\nspec:\n  strategy:\n    canary:\n      steps:\n      - setWeight: 100\n"))
+	cmd.SetArgs([]string{"lint", "-"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if out := stdout.String(); !strings.Contains(out, "<stdin>") || !strings.Contains(out, "no-pause-before-full") {
+		t.Errorf("unexpected table output:\n%s", stdout.String())
+	}
+}
+
+func TestDiff(t *testing.T) {
+	live := newTestRollout("web")
+	live.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	live.Kind = v1alpha1.RolloutKind
+	client := fake.NewClientset("dev", live)
+
+	changed := live.DeepCopy()
+	changed.Spec.Template.Spec.Containers[0].Image = "nginx:1.24"
+	data, err := json.Marshal(changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "web.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, client, "diff", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "a new revision will be rolled out") || !strings.Contains(out, `"nginx:1.23" -> "nginx:1.24"`) {
+		t.Errorf("unexpected text output:\n%s", out)
+	}
+
+	out, err = run(t, client, "diff", path, path, "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result diff.Result
+	if err := json.Unmarshal([]byte(out), &result); err != nil || result.Category != diff.CategoryNone {
+		t.Errorf("unexpected json output %s: %v", out, err)
+	}
+
+	typo := filepath.Join(t.TempDir(), "typo.yaml")
+	if err := os.WriteFile(typo, []byte("apiVersion: argoproj.io/v1alpha1\nkind: Rollout\nmetadata:\n  name: web\nspec:\n  replica: 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(t, client, "diff", typo); err == nil || !strings.Contains(err.Error(), `line 6: unknown field "spec.replica"`) {
+		t.Errorf("expected an unknown field error, got %v", err)
+	}
+}
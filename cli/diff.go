@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/chamhaw/kubernetes-rollout-api/decode"
+	"github.com/chamhaw/kubernetes-rollout-api/diff"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+func newDiffCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff [OLD_FILE] NEW_FILE",
+		Short: "Classify the changes between two versions of a rollout",
+		Long: "Classify the changes between two versions of a rollout: pod template changes creating a new revision, " +
+			"canary step changes, other spec changes and metadata changes. With a single file, the rollout of the " +
+			"file is compared to the rollout in the cluster. \"-\" reads the standard input.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newRollout, err := readRollout(cmd.InOrStdin(), args[len(args)-1])
+			if err != nil {
+				return err
+			}
+			var oldRollout *v1alpha1.Rollout
+			if len(args) == 2 {
+				oldRollout, err = readRollout(cmd.InOrStdin(), args[0])
+			} else {
+				namespace := newRollout.Namespace
+				if namespace == "" {
+					namespace = o.Namespace
+				}
+				oldRollout, err = o.Client.Rollouts(namespace).Get(cmd.Context(), o.Cluster, namespace, newRollout.Name)
+			}
+			if err != nil {
+				return err
+			}
+
+			result, err := diff.Compare(oldRollout, newRollout)
+			if err != nil {
+				return err
+			}
+			switch o.Output {
+			case OutputJSON:
+				data, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(o.Out, string(data))
+				return err
+			case OutputYAML:
+				data, err := yaml.Marshal(result)
+				if err != nil {
+					return err
+				}
+				_, err = o.Out.Write(data)
+				return err
+			}
+			return result.WriteText(o.Out)
+		},
+	}
+}
+
+// readRollout strictly decodes a YAML or JSON rollout from the file at path, or from stdin when path is "-".
+func readRollout(stdin io.Reader, path string) (*v1alpha1.Rollout, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ro, err := decode.Rollout(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ro, nil
+}
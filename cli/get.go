@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/yaml"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+func newGetCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get NAME",
+		Short: "Display a rollout",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ro, err := o.get(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return o.print(ro, []v1alpha1.Rollout{*ro})
+		},
+	}
+}
+
+func newListCommand(o *Options) *cobra.Command {
+	var selector string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the rollouts of the namespace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			listWatcher, err := o.listWatcher()
+			if err != nil {
+				return err
+			}
+			list, err := listWatcher.List(cmd.Context(), o.Cluster, o.Namespace, metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return err
+			}
+			list.APIVersion = v1alpha1.SchemeGroupVersion.String()
+			list.Kind = v1alpha1.RolloutKind + "List"
+			return o.print(list, list.Items)
+		},
+	}
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Label selector to filter the rollouts")
+	return cmd
+}
+
+func newStatusCommand(o *Options) *cobra.Command {
+	var watchStatus bool
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "status NAME",
+		Short: "Show the status of a rollout",
+		Long: "Show the status of a rollout. With --watch, the status is printed on every change until the rollout " +
+			"is Healthy or Degraded, the command fails if the rollout is Degraded.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			return o.status(ctx, args[0], watchStatus)
+		},
+	}
+	cmd.Flags().BoolVarP(&watchStatus, "watch", "w", false, "Watch the status until the rollout is Healthy or Degraded")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Time to wait for the rollout when watching, 0 means no timeout")
+	return cmd
+}
+
+func (o *Options) status(ctx context.Context, name string, watchStatus bool) error {
+	ro, err := o.get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !watchStatus {
+		o.printStatus(ro)
+		return nil
+	}
+
+	listWatcher, err := o.listWatcher()
+	if err != nil {
+		return err
+	}
+	// the watch starts from the resource version of the last seen rollout, so no change is missed when it
+	// is re-established after the server closed it
+	watchRollout := func() (watch.Interface, error) {
+		return listWatcher.Watch(ctx, o.Cluster, o.Namespace, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion: ro.ResourceVersion,
+		})
+	}
+	w, err := watchRollout()
+	if err != nil {
+		return err
+	}
+	defer func() { w.Stop() }()
+	last := ""
+	for {
+		if line := statusLine(ro); line != last {
+			o.printStatus(ro)
+			last = line
+		}
+		if done, err := rolloutDone(ro); done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for rollout %s: %w", name, ctx.Err())
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				w.Stop()
+				if w, err = watchRollout(); err != nil {
+					return err
+				}
+				continue
+			}
+			if event.Type == watch.Error {
+				return fmt.Errorf("watch of rollout %s failed: %w", name, apierrors.FromObject(event.Object))
+			}
+			updated, isRollout := event.Object.(*v1alpha1.Rollout)
+			if !isRollout || updated.Name != name {
+				continue
+			}
+			if event.Type == watch.Deleted {
+				return fmt.Errorf("rollout %s was deleted", name)
+			}
+			ro = updated
+		}
+	}
+}
+
+// rolloutDone returns true when the rollout reached a final phase for its current generation, with an
+// error if the phase is Degraded.
+func rolloutDone(ro *v1alpha1.Rollout) (bool, error) {
+	if ro.Status.ObservedGeneration != ro.Generation {
+		return false, nil
+	}
+	switch ro.Status.Phase {
+	case v1alpha1.RolloutPhaseHealthy:
+		return true, nil
+	case v1alpha1.RolloutPhaseDegraded:
+		return true, fmt.Errorf("rollout %s is degraded: %s", ro.Name, ro.Status.Message)
+	}
+	return false, nil
+}
+
+func statusLine(ro *v1alpha1.Rollout) string {
+	if ro.Status.Message == "" {
+		return string(ro.Status.Phase)
+	}
+	return fmt.Sprintf("%s - %s", ro.Status.Phase, ro.Status.Message)
+}
+
+func (o *Options) printStatus(ro *v1alpha1.Rollout) {
+	fmt.Fprintln(o.Out, statusLine(ro))
+}
+
+// print prints obj as JSON or YAML, or the rollouts as a table.
+func (o *Options) print(obj runtime.Object, rollouts []v1alpha1.Rollout) error {
+	switch o.Output {
+	case OutputJSON:
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(o.Out, string(data))
+		return err
+	case OutputYAML:
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		_, err = o.Out.Write(data)
+		return err
+	}
+	return printTable(o.Out, rollouts, time.Now())
+}
+
+func printTable(out io.Writer, rollouts []v1alpha1.Rollout, now time.Time) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTRATEGY\tSTATUS\tSTEP\tSET-WEIGHT\tDESIRED\tCURRENT\tUP-TO-DATE\tAVAILABLE\tAGE")
+	for i := range rollouts {
+		ro := &rollouts[i]
+		strategy, step, weight := "-", "-", "-"
+		if canary := ro.Spec.Strategy.Canary; canary != nil {
+			strategy = "Canary"
+			if len(canary.Steps) > 0 {
+				_, index := v1alpha1.GetCurrentCanaryStep(ro)
+				current := int32(0)
+				if index != nil {
+					current = *index
+				}
+				step = fmt.Sprintf("%d/%d", current, len(canary.Steps))
+			}
+			weight = strconv.Itoa(int(v1alpha1.GetCurrentSetWeight(ro)))
+		}
+		desired := v1alpha1.DefaultReplicas
+		if ro.Spec.Replicas != nil {
+			desired = *ro.Spec.Replicas
+		}
+		age := "<unknown>"
+		if !ro.CreationTimestamp.IsZero() {
+			age = duration.HumanDuration(now.Sub(ro.CreationTimestamp.Time))
+		}
+		phase := string(ro.Status.Phase)
+		if phase == "" {
+			phase = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n", ro.Name, strategy, phase, step, weight,
+			desired, ro.Status.Replicas, ro.Status.UpdatedReplicas, ro.Status.AvailableReplicas, age)
+	}
+	return w.Flush()
+}
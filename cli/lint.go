@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/chamhaw/kubernetes-rollout-api/lint"
+)
+
+func newLintCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint FILE...",
+		Short: "Report likely mistakes in Rollout manifests",
+		Long: "Report likely mistakes in the Rollout and RolloutList documents of YAML or JSON files, \"-\" reads " +
+			"the standard input. The command fails if a problem has the error severity.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var problems []lint.Problem
+			for _, path := range args {
+				var fileProblems []lint.Problem
+				var err error
+				if path == "-" {
+					fileProblems, err = lint.LintReader(cmd.InOrStdin(), "<stdin>")
+				} else {
+					fileProblems, err = lint.LintFiles(path)
+				}
+				if err != nil {
+					return err
+				}
+				problems = append(problems, fileProblems...)
+			}
+			if err := o.printProblems(problems); err != nil {
+				return err
+			}
+			if lint.HasErrors(problems) {
+				return fmt.Errorf("lint found errors")
+			}
+			return nil
+		},
+	}
+}
+
+func (o *Options) printProblems(problems []lint.Problem) error {
+	if problems == nil {
+		problems = []lint.Problem{}
+	}
+	switch o.Output {
+	case OutputJSON:
+		data, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(o.Out, string(data))
+		return err
+	case OutputYAML:
+		data, err := yaml.Marshal(problems)
+		if err != nil {
+			return err
+		}
+		_, err = o.Out.Write(data)
+		return err
+	}
+	if len(problems) == 0 {
+		_, err := fmt.Fprintln(o.Out, "No problems found")
+		return err
+	}
+	w := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tROLLOUT\tSEVERITY\tRULE\tFIELD\tMESSAGE")
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", p.Source, p.Rollout, p.Severity, p.Rule, p.Field, p.Message)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/chamhaw/kubernetes-rollout-api/apply"
+	applyv1alpha1 "github.com/chamhaw/kubernetes-rollout-api/applyconfiguration/v1alpha1"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+// kubeconfigClient is a RolloutsGetter using a dynamic client per kubeconfig context, the clusterCode
+// being the context name. An empty clusterCode uses the current context.
+type kubeconfigClient struct {
+	loadingRules *clientcmd.ClientConfigLoadingRules
+
+	mu      sync.Mutex
+	clients map[string]dynamic.Interface
+}
+
+func newKubeconfigClient(loadingRules *clientcmd.ClientConfigLoadingRules) *kubeconfigClient {
+	return &kubeconfigClient{loadingRules: loadingRules, clients: map[string]dynamic.Interface{}}
+}
+
+func (c *kubeconfigClient) Rollouts(namespace string) v1alpha1.RolloutInterface {
+	return &kubeconfigRollouts{client: c, namespace: namespace}
+}
+
+func (c *kubeconfigClient) resource(clusterCode, namespace string) (dynamic.ResourceInterface, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	client, ok := c.clients[clusterCode]
+	if !ok {
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: clusterCode}
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(c.loadingRules, overrides).ClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		client, err = dynamic.NewForConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		c.clients[clusterCode] = client
+	}
+	return client.Resource(v1alpha1.RolloutGVR).Namespace(namespace), nil
+}
+
+type kubeconfigRollouts struct {
+	client    *kubeconfigClient
+	namespace string
+}
+
+var (
+	_ apply.RolloutApplier                = &kubeconfigRollouts{}
+	_ v1alpha1.RolloutListWatcher         = &kubeconfigRollouts{}
+	_ v1alpha1.RolloutPreconditionDeleter = &kubeconfigRollouts{}
+)
+
+func (r *kubeconfigRollouts) resource(clusterCode, namespace string) (dynamic.ResourceInterface, error) {
+	if namespace == "" {
+		namespace = r.namespace
+	}
+	return r.client.resource(clusterCode, namespace)
+}
+
+func (r *kubeconfigRollouts) Create(ctx context.Context, clusterCode string, rollout *v1alpha1.Rollout) (*v1alpha1.Rollout, error) {
+	return r.write(ctx, clusterCode, rollout, func(res dynamic.ResourceInterface, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		return res.Create(ctx, u, metav1.CreateOptions{})
+	})
+}
+
+func (r *kubeconfigRollouts) Update(ctx context.Context, clusterCode string, rollout *v1alpha1.Rollout) (*v1alpha1.Rollout, error) {
+	return r.write(ctx, clusterCode, rollout, func(res dynamic.ResourceInterface, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		return res.Update(ctx, u, metav1.UpdateOptions{})
+	})
+}
+
+func (r *kubeconfigRollouts) UpdateStatus(ctx context.Context, clusterCode string, rollout *v1alpha1.Rollout) (*v1alpha1.Rollout, error) {
+	return r.write(ctx, clusterCode, rollout, func(res dynamic.ResourceInterface, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		return res.UpdateStatus(ctx, u, metav1.UpdateOptions{})
+	})
+}
+
+func (r *kubeconfigRollouts) write(ctx context.Context, clusterCode string, rollout *v1alpha1.Rollout,
+	write func(res dynamic.ResourceInterface, u *unstructured.Unstructured) (*unstructured.Unstructured, error)) (*v1alpha1.Rollout, error) {
+	res, err := r.resource(clusterCode, rollout.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(rollout)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetGroupVersionKind(v1alpha1.RolloutGVK)
+	result, err := write(res, u)
+	if err != nil {
+		return nil, err
+	}
+	return fromUnstructured(result)
+}
+
+func (r *kubeconfigRollouts) Apply(ctx context.Context, clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration, opts metav1.ApplyOptions) (*v1alpha1.Rollout, error) {
+	return r.apply(ctx, clusterCode, rollout, func(res dynamic.ResourceInterface, name string, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		return res.Apply(ctx, name, u, opts)
+	})
+}
+
+func (r *kubeconfigRollouts) ApplyStatus(ctx context.Context, clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration, opts metav1.ApplyOptions) (*v1alpha1.Rollout, error) {
+	return r.apply(ctx, clusterCode, rollout, func(res dynamic.ResourceInterface, name string, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		return res.ApplyStatus(ctx, name, u, opts)
+	})
+}
+
+func (r *kubeconfigRollouts) apply(ctx context.Context, clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration,
+	apply func(res dynamic.ResourceInterface, name string, u *unstructured.Unstructured) (*unstructured.Unstructured, error)) (*v1alpha1.Rollout, error) {
+	if rollout == nil || rollout.Name == nil {
+		return nil, fmt.Errorf("rollout provided to Apply must not be nil and must have a name")
+	}
+	namespace := ""
+	if rollout.Namespace != nil {
+		namespace = *rollout.Namespace
+	}
+	res, err := r.resource(clusterCode, namespace)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(rollout)
+	if err != nil {
+		return nil, err
+	}
+	result, err := apply(res, *rollout.Name, &unstructured.Unstructured{Object: obj})
+	if err != nil {
+		return nil, err
+	}
+	return fromUnstructured(result)
+}
+
+func (r *kubeconfigRollouts) Delete(ctx context.Context, clusterCode, namespace, name string) (*v1alpha1.Rollout, error) {
+	return r.delete(ctx, clusterCode, namespace, name, nil)
+}
+
+func (r *kubeconfigRollouts) DeleteWithPreconditions(ctx context.Context, clusterCode, namespace, name string, preconditions metav1.Preconditions) (*v1alpha1.Rollout, error) {
+	return r.delete(ctx, clusterCode, namespace, name, &preconditions)
+}
+
+// delete returns the rollout read before deleting it, preconditions may be nil.
+func (r *kubeconfigRollouts) delete(ctx context.Context, clusterCode, namespace, name string, preconditions *metav1.Preconditions) (*v1alpha1.Rollout, error) {
+	ro, err := r.Get(ctx, clusterCode, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.resource(clusterCode, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := res.Delete(ctx, name, metav1.DeleteOptions{Preconditions: preconditions}); err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
+
+func (r *kubeconfigRollouts) Get(ctx context.Context, clusterCode, namespace, name string) (*v1alpha1.Rollout, error) {
+	res, err := r.resource(clusterCode, namespace)
+	if err != nil {
+		return nil, err
+	}
+	u, err := res.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return fromUnstructured(u)
+}
+
+func (r *kubeconfigRollouts) List(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (*v1alpha1.RolloutList, error) {
+	res, err := r.resource(clusterCode, namespace)
+	if err != nil {
+		return nil, err
+	}
+	list, err := res.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	rollouts := &v1alpha1.RolloutList{ListMeta: metav1.ListMeta{ResourceVersion: list.GetResourceVersion()}}
+	for i := range list.Items {
+		ro, err := fromUnstructured(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		rollouts.Items = append(rollouts.Items, *ro)
+	}
+	return rollouts, nil
+}
+
+func (r *kubeconfigRollouts) Watch(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	res, err := r.resource(clusterCode, namespace)
+	if err != nil {
+		return nil, err
+	}
+	w, err := res.Watch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		u, ok := in.Object.(*unstructured.Unstructured)
+		if !ok {
+			return in, true
+		}
+		// error events carry a Status, passed through for apierrors.FromObject
+		if in.Type == watch.Error {
+			status := &metav1.Status{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, status); err != nil {
+				return in, true
+			}
+			return watch.Event{Type: in.Type, Object: status}, true
+		}
+		ro, err := fromUnstructured(u)
+		if err != nil {
+			return in, false
+		}
+		return watch.Event{Type: in.Type, Object: ro}, true
+	}), nil
+}
+
+func fromUnstructured(u *unstructured.Unstructured) (*v1alpha1.Rollout, error) {
+	ro := &v1alpha1.Rollout{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, ro); err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+func TestKubeconfigRolloutsWatch(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	fakeWatch := watch.NewFake()
+	client.PrependWatchReactor("*", func(clienttesting.Action) (bool, watch.Interface, error) {
+		return true, fakeWatch, nil
+	})
+	rollouts := newKubeconfigClient(nil)
+	rollouts.clients = map[string]dynamic.Interface{"dev": client}
+
+	w, err := (&kubeconfigRollouts{client: rollouts, namespace: "default"}).Watch(context.Background(), "dev", "default", metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	ro := &unstructured.Unstructured{}
+	ro.SetGroupVersionKind(v1alpha1.RolloutGVK)
+	ro.SetName("web")
+	go fakeWatch.Modify(ro)
+	event := <-w.ResultChan()
+	if converted, ok := event.Object.(*v1alpha1.Rollout); !ok || converted.Name != "web" {
+		t.Errorf("expected the rollout web, got %v", event.Object)
+	}
+
+	gone := apierrors.NewResourceExpired("too old resource version")
+	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&gone.ErrStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go fakeWatch.Error(&unstructured.Unstructured{Object: status})
+	event = <-w.ResultChan()
+	if event.Type != watch.Error || !apierrors.IsResourceExpired(apierrors.FromObject(event.Object)) {
+		t.Errorf("expected the error event to carry the status, got %v", event.Object)
+	}
+}
@@ -0,0 +1,23 @@
+// Command rollouts operates the Rollouts of the clusters of the kubeconfig, the --cluster flag selects
+// the kubeconfig context.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/chamhaw/kubernetes-rollout-api/cli"
+)
+
+func main() {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	client := newKubeconfigClient(loadingRules)
+	cmd := cli.NewCommand(client, os.Stdout, os.Stderr)
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
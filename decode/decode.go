@@ -0,0 +1,212 @@
+// Package decode decodes Rollout YAML and JSON manifests strictly: unknown and duplicate fields are reported
+// with their line numbers instead of being silently dropped.
+package decode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kjson "sigs.k8s.io/json"
+	"sigs.k8s.io/yaml"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+// FieldErrorType is the type of a strict decoding error
+type FieldErrorType string
+
+const (
+	// FieldErrorUnknown is a field that does not exist in the schema
+	FieldErrorUnknown FieldErrorType = "unknown field"
+	// FieldErrorDuplicate is a field set more than once in the same object
+	FieldErrorDuplicate FieldErrorType = "duplicate field"
+)
+
+// FieldError is an unknown or duplicate field.
+type FieldError struct {
+	Type FieldErrorType
+	// Document is the index of the document in the stream, starting at 1
+	Document int
+	// Line and Column locate the field in the stream, starting at 1. They are 0 if unknown.
+	Line   int
+	Column int
+	// Path of the field, for example spec.strategy.canary.steps[0].setWieght
+	Path string
+}
+
+func (e *FieldError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("document %d: %s %q", e.Document, e.Type, e.Path)
+	}
+	return fmt.Sprintf("document %d, line %d: %s %q", e.Document, e.Line, e.Type, e.Path)
+}
+
+// StrictError is returned when the stream could be decoded but has unknown or duplicate fields.
+type StrictError struct {
+	Errors []*FieldError
+}
+
+func (e *StrictError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return "strict decoding error: " + strings.Join(msgs, ", ")
+}
+
+// IsStrictError returns true if err only reports unknown or duplicate fields, in which case the rollouts
+// decoded alongside it are usable.
+func IsStrictError(err error) bool {
+	var strictErr *StrictError
+	return errors.As(err, &strictErr)
+}
+
+// Rollouts decodes the Rollout and RolloutList documents of a YAML stream, or a JSON document. The items
+// of the lists are returned in place of the lists. Empty documents are skipped, documents of any other kind
+// are an error.
+// If the only problems are unknown or duplicate fields, the decoded rollouts are returned with a *StrictError.
+func Rollouts(r io.Reader) ([]v1alpha1.Rollout, error) {
+	decoder := yamlv3.NewDecoder(r)
+	var rollouts []v1alpha1.Rollout
+	strictErr := &StrictError{}
+	for doc := 1; ; doc++ {
+		var node yamlv3.Node
+		if err := decoder.Decode(&node); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		docRollouts, fieldErrs, err := decodeDocument(&node)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		for _, fieldErr := range fieldErrs {
+			fieldErr.Document = doc
+			strictErr.Errors = append(strictErr.Errors, fieldErr)
+		}
+		rollouts = append(rollouts, docRollouts...)
+	}
+	if len(strictErr.Errors) > 0 {
+		return rollouts, strictErr
+	}
+	return rollouts, nil
+}
+
+// Rollout decodes a single Rollout from YAML or JSON data, with the same strictness as Rollouts.
+func Rollout(data []byte) (*v1alpha1.Rollout, error) {
+	rollouts, err := Rollouts(bytes.NewReader(data))
+	if err != nil && !IsStrictError(err) {
+		return nil, err
+	}
+	if len(rollouts) != 1 {
+		return nil, fmt.Errorf("expected a single %s, got %d", v1alpha1.RolloutKind, len(rollouts))
+	}
+	return &rollouts[0], err
+}
+
+func decodeDocument(node *yamlv3.Node) ([]v1alpha1.Rollout, []*FieldError, error) {
+	if len(node.Content) == 0 || node.Content[0].Tag == "!!null" {
+		return nil, nil, nil
+	}
+	locations := map[string]*yamlv3.Node{}
+	var fieldErrs []*FieldError
+	index(node.Content[0], "", locations, &fieldErrs)
+
+	content, err := yamlv3.Marshal(node)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var typeMeta metav1.TypeMeta
+	if err := kjson.UnmarshalCaseSensitivePreserveInts(data, &typeMeta); err != nil {
+		return nil, nil, err
+	}
+	gvk := schema.FromAPIVersionAndKind(typeMeta.APIVersion, typeMeta.Kind)
+	if gvk.GroupVersion() != v1alpha1.SchemeGroupVersion {
+		return nil, nil, fmt.Errorf("unsupported apiVersion %q, expected %s", typeMeta.APIVersion, v1alpha1.SchemeGroupVersion)
+	}
+
+	var rollouts []v1alpha1.Rollout
+	var strictErrs []error
+	switch gvk.Kind {
+	case v1alpha1.RolloutKind:
+		var ro v1alpha1.Rollout
+		strictErrs, err = kjson.UnmarshalStrict(data, &ro, kjson.DisallowUnknownFields)
+		rollouts = []v1alpha1.Rollout{ro}
+	case v1alpha1.RolloutKind + "List":
+		var list v1alpha1.RolloutList
+		strictErrs, err = kjson.UnmarshalStrict(data, &list, kjson.DisallowUnknownFields)
+		rollouts = list.Items
+	default:
+		return nil, nil, fmt.Errorf("unsupported kind %q, expected %s or %sList", gvk.Kind, v1alpha1.RolloutKind, v1alpha1.RolloutKind)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, strictErr := range strictErrs {
+		fieldErr := &FieldError{Type: FieldErrorUnknown, Path: strictErr.Error()}
+		if pathErr, ok := strictErr.(kjson.FieldError); ok {
+			fieldErr.Path = pathErr.FieldPath()
+		}
+		if location, ok := locations[fieldErr.Path]; ok {
+			fieldErr.Line, fieldErr.Column = location.Line, location.Column
+		}
+		fieldErrs = append(fieldErrs, fieldErr)
+	}
+	sort.SliceStable(fieldErrs, func(i, j int) bool {
+		return fieldErrs[i].Line < fieldErrs[j].Line
+	})
+	return rollouts, fieldErrs, nil
+}
+
+// index records the key node of each field by its path, in the format of the paths of the strict JSON
+// errors, and reports the duplicate keys of the mappings.
+func index(node *yamlv3.Node, path string, locations map[string]*yamlv3.Node, fieldErrs *[]*FieldError) {
+	switch node.Kind {
+	case yamlv3.AliasNode:
+		index(node.Alias, path, locations, fieldErrs)
+	case yamlv3.MappingNode:
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Tag == "!!merge" {
+				continue
+			}
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			if seen[key.Value] {
+				*fieldErrs = append(*fieldErrs, &FieldError{
+					Type:   FieldErrorDuplicate,
+					Line:   key.Line,
+					Column: key.Column,
+					Path:   childPath,
+				})
+				continue
+			}
+			seen[key.Value] = true
+			locations[childPath] = key
+			index(value, childPath, locations, fieldErrs)
+		}
+	case yamlv3.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := path + "[" + strconv.Itoa(i) + "]"
+			locations[itemPath] = item
+			index(item, itemPath, locations, fieldErrs)
+		}
+	}
+}
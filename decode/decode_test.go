@@ -0,0 +1,122 @@
+package decode
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+func names(rollouts []v1alpha1.Rollout) []string {
+	var result []string
+	for _, ro := range rollouts {
+		result = append(result, ro.Name)
+	}
+	return result
+}
+
+func TestRolloutsStream(t *testing.T) {
+	f, err := os.Open("testdata/stream.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	rollouts, err := Rollouts(f)
+	if !IsStrictError(err) {
+		t.Fatalf("Rollouts() error = %v, want a strict error", err)
+	}
+	if got, want := names(rollouts), []string{"web", "api", "worker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Rollouts() names = %v, want %v", got, want)
+	}
+	if rollouts[0].Spec.Strategy.Canary.Steps[1].Pause.DurationSeconds() != 3600 {
+		t.Errorf("unexpected steps %v", rollouts[0].Spec.Strategy.Canary.Steps)
+	}
+
+	want := []FieldError{
+		{Type: FieldErrorDuplicate, Document: 3, Line: 26, Column: 7, Path: "items[0].metadata.labels.team"},
+		{Type: FieldErrorUnknown, Document: 3, Line: 32, Column: 11, Path: "items[0].spec.strategy.canary.steps[0].setWieght"},
+		{Type: FieldErrorDuplicate, Document: 3, Line: 40, Column: 5, Path: "items[1].spec.replicas"},
+	}
+	var got []FieldError
+	for _, fieldErr := range err.(*StrictError).Errors {
+		got = append(got, *fieldErr)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("field errors = %+v, want %+v", got, want)
+	}
+	if msg := err.Error(); !strings.Contains(msg, `document 3, line 32: unknown field "items[0].spec.strategy.canary.steps[0].setWieght"`) {
+		t.Errorf("unexpected error message %s", msg)
+	}
+}
+
+func TestRollout(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+		strict  bool
+	}{
+		{
+			name: "valid yaml",
+			data: "apiVersion: argoproj.io/v1alpha1\nkind: Rollout\nmetadata:\n  name: web\n",
+		},
+		{
+			name: "valid json",
+			data: `{"apiVersion": "argoproj.io/v1alpha1", "kind": "Rollout", "metadata": {"name": "web"}}`,
+		},
+		{
+			name:    "unknown json field",
+			data:    "{\n  \"apiVersion\": \"argoproj.io/v1alpha1\",\n  \"kind\": \"Rollout\",\n  \"metadata\": {\"name\": \"web\"},\n  \"spec\": {\"replica\": 3}\n}",
+			wantErr: `document 1, line 5: unknown field "spec.replica"`,
+			strict:  true,
+		},
+		{
+			name:    "wrong kind",
+			data:    "apiVersion: argoproj.io/v1alpha1\nkind: Experiment\n",
+			wantErr: `document 1: unsupported kind "Experiment"`,
+		},
+		{
+			name:    "wrong api version",
+			data:    "apiVersion: apps/v1\nkind: Rollout\n",
+			wantErr: `document 1: unsupported apiVersion "apps/v1"`,
+		},
+		{
+			name:    "wrong type",
+			data:    "apiVersion: argoproj.io/v1alpha1\nkind: Rollout\nspec:\n  replicas: three\n",
+			wantErr: "cannot unmarshal string",
+		},
+		{
+			name:    "invalid yaml",
+			data:    "kind: [Rollout\n",
+			wantErr: "document 1: yaml",
+		},
+		{
+			name:    "several rollouts",
+			data:    "apiVersion: argoproj.io/v1alpha1\nkind: RolloutList\nitems: []\n",
+			wantErr: "expected a single Rollout, got 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ro, err := Rollout([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil || ro.Name != "web" {
+					t.Errorf("Rollout() = %v, %v, want web", ro, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Rollout() error = %v, want %q", err, tt.wantErr)
+			}
+			if IsStrictError(err) != tt.strict {
+				t.Errorf("IsStrictError() = %v, want %v", IsStrictError(err), tt.strict)
+			}
+			if tt.strict && (ro == nil || ro.Name != "web") {
+				t.Errorf("Rollout() = %v, want the decoded rollout with a strict error", ro)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// Package diff compares two versions of a Rollout and classifies the changes by their effect on the rollout.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+// Category is the effect of a change on the rollout
+type Category string
+
+const (
+	// CategoryNone means the two versions are equivalent
+	CategoryNone Category = "none"
+	// CategoryMetadata is a change of the labels or annotations of the rollout only
+	CategoryMetadata Category = "metadata"
+	// CategorySpec is a change of the spec that neither creates a revision nor changes the steps
+	CategorySpec Category = "spec"
+	// CategorySteps is a change of the canary steps, which changes Status.CurrentStepHash
+	CategorySteps Category = "steps"
+	// CategoryPodTemplate is a change of the pod template, which creates a new revision
+	CategoryPodTemplate Category = "pod-template"
+)
+
+// categoryOrder orders the categories by impact
+var categoryOrder = map[Category]int{
+	CategoryNone:        0,
+	CategoryMetadata:    1,
+	CategorySpec:        2,
+	CategorySteps:       3,
+	CategoryPodTemplate: 4,
+}
+
+// stepsPath is the path of the canary steps
+const stepsPath = "spec.strategy.canary.steps"
+
+// ignoredFields are set by the server or the controller and are not compared
+var ignoredFields = map[string]bool{
+	"status":                     true,
+	"metadata.resourceVersion":   true,
+	"metadata.generation":        true,
+	"metadata.uid":               true,
+	"metadata.creationTimestamp": true,
+	"metadata.managedFields":     true,
+	"metadata.selfLink":          true,
+}
+
+// Change is the change of a single field.
+type Change struct {
+	Category Category `json:"category"`
+	// Path of the field, for example spec.template.spec.containers[0].image
+	Path string `json:"path"`
+	// Old and New are the JSON values of the field, empty when the field is not set
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// Result is the outcome of comparing two versions of a Rollout.
+type Result struct {
+	// Category is the category of the change with the highest impact
+	Category Category `json:"category"`
+	// NewRevision is true when the pod template hash changed
+	NewRevision        bool   `json:"newRevision"`
+	OldPodTemplateHash string `json:"oldPodTemplateHash"`
+	NewPodTemplateHash string `json:"newPodTemplateHash"`
+	// StepsChanged is true when the step hash changed
+	StepsChanged bool   `json:"stepsChanged"`
+	OldStepHash  string `json:"oldStepHash,omitempty"`
+	NewStepHash  string `json:"newStepHash,omitempty"`
+	// StepChange tells how a rollout at the current step of the old version is affected by the new steps
+	StepChange *v1alpha1.StepChangeResult `json:"stepChange,omitempty"`
+	Changes    []Change                   `json:"changes"`
+}
+
+// Compare compares the old and the new version of a rollout. The status of the old version is used to
+// evaluate the step changes against its current step.
+func Compare(oldRollout, newRollout *v1alpha1.Rollout) (*Result, error) {
+	result := &Result{
+		Category:           CategoryNone,
+		OldPodTemplateHash: v1alpha1.ComputePodTemplateHash(&oldRollout.Spec.Template, oldRollout.Status.CollisionCount),
+		NewPodTemplateHash: v1alpha1.ComputePodTemplateHash(&newRollout.Spec.Template, oldRollout.Status.CollisionCount),
+		Changes:            []Change{},
+	}
+	result.NewRevision = result.OldPodTemplateHash != result.NewPodTemplateHash
+
+	oldCanary, newCanary := oldRollout.Spec.Strategy.Canary, newRollout.Spec.Strategy.Canary
+	if oldCanary != nil {
+		result.OldStepHash = v1alpha1.ComputeStepHash(oldCanary)
+	}
+	if newCanary != nil {
+		result.NewStepHash = v1alpha1.ComputeStepHash(newCanary)
+	}
+	result.StepsChanged = result.OldStepHash != result.NewStepHash
+	if result.StepsChanged {
+		stepChange := v1alpha1.CompareCanarySteps(oldCanary, newCanary, oldRollout.Status.CurrentStepIndex)
+		result.StepChange = &stepChange
+	}
+
+	oldFields, err := toFields(oldRollout)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := toFields(newRollout)
+	if err != nil {
+		return nil, err
+	}
+	walk("", oldFields, newFields, func(path string, oldValue, newValue interface{}) {
+		category := categorize(path)
+		// a canary strategy added or removed as a whole also changes the steps
+		if result.StepsChanged && category == CategorySpec && hasPathPrefix(stepsPath, path) {
+			category = CategorySteps
+		}
+		result.Changes = append(result.Changes, Change{
+			Category: category,
+			Path:     path,
+			Old:      encode(oldValue),
+			New:      encode(newValue),
+		})
+	})
+
+	for _, change := range result.Changes {
+		if categoryOrder[change.Category] > categoryOrder[result.Category] {
+			result.Category = change.Category
+		}
+	}
+	if result.StepsChanged && categoryOrder[result.Category] < categoryOrder[CategorySteps] {
+		result.Category = CategorySteps
+	}
+	return result, nil
+}
+
+func toFields(rollout *v1alpha1.Rollout) (map[string]interface{}, error) {
+	data, err := json.Marshal(rollout)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// walk calls changed for each leaf value that differs between a and b. Lists of different length are
+// reported as a single change.
+func walk(path string, a, b interface{}, changed func(path string, a, b interface{})) {
+	if ignoredFields[path] || reflect.DeepEqual(a, b) {
+		return
+	}
+	switch aValue := a.(type) {
+	case map[string]interface{}:
+		bValue, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := map[string]bool{}
+		for key := range aValue {
+			keys[key] = true
+		}
+		for key := range bValue {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		for _, key := range sorted {
+			walk(childPath(path, key), aValue[key], bValue[key], changed)
+		}
+		return
+	case []interface{}:
+		bValue, ok := b.([]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			break
+		}
+		for i := range aValue {
+			walk(fmt.Sprintf("%s[%d]", path, i), aValue[i], bValue[i], changed)
+		}
+		return
+	}
+	changed(path, a, b)
+}
+
+func childPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func categorize(path string) Category {
+	switch {
+	case hasPathPrefix(path, "spec.template"):
+		return CategoryPodTemplate
+	case hasPathPrefix(path, stepsPath):
+		return CategorySteps
+	case hasPathPrefix(path, "spec"):
+		return CategorySpec
+	}
+	return CategoryMetadata
+}
+
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+".") || strings.HasPrefix(path, prefix+"[")
+}
+
+func encode(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// WriteText writes a human readable summary of the result.
+func (r *Result) WriteText(w io.Writer) error {
+	var b strings.Builder
+	switch r.Category {
+	case CategoryNone:
+		b.WriteString("No changes\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	case CategoryPodTemplate:
+		b.WriteString("Pod template changed: a new revision will be rolled out\n")
+	case CategorySteps:
+		b.WriteString("Canary steps changed\n")
+	case CategorySpec:
+		b.WriteString("Spec changed: no new revision\n")
+	case CategoryMetadata:
+		b.WriteString("Metadata changed only\n")
+	}
+	fmt.Fprintf(&b, "Pod template hash: %s\n", transition(r.OldPodTemplateHash, r.NewPodTemplateHash))
+	if r.OldStepHash != "" || r.NewStepHash != "" {
+		fmt.Fprintf(&b, "Step hash: %s\n", transition(r.OldStepHash, r.NewStepHash))
+	}
+	if r.StepChange != nil {
+		if r.StepChange.Restart {
+			fmt.Fprintf(&b, "Steps: %s, the rollout restarts from step 0\n", r.StepChange.Change)
+		} else {
+			fmt.Fprintf(&b, "Steps: %s, the rollout continues at step %d\n", r.StepChange.Change, r.StepChange.StepIndex)
+		}
+	}
+	b.WriteString("Changes:\n")
+	for _, change := range r.Changes {
+		fmt.Fprintf(&b, "  [%s] %s: %s -> %s\n", change.Category, change.Path, orUnset(change.Old), orUnset(change.New))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func transition(oldValue, newValue string) string {
+	if oldValue == newValue {
+		return oldValue + " (unchanged)"
+	}
+	return orUnset(oldValue) + " -> " + orUnset(newValue)
+}
+
+func orUnset(value string) string {
+	if value == "" {
+		return "<unset>"
+	}
+	return value
+}
@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func newRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps", Labels: map[string]string{"team": "a"}, ResourceVersion: "1"},
+		Spec: v1alpha1.RolloutSpec{
+			Replicas: int32Ptr(3),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "nginx:1.23"}}},
+			},
+			Strategy: v1alpha1.RolloutStrategy{Canary: &v1alpha1.CanaryStrategy{
+				Steps: []v1alpha1.CanaryStep{{SetWeight: int32Ptr(20)}, {Pause: &v1alpha1.RolloutPause{}}, {SetWeight: int32Ptr(50)}},
+			}},
+		},
+		Status: v1alpha1.RolloutStatus{CurrentStepIndex: int32Ptr(1)},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name         string
+		mutate       func(ro *v1alpha1.Rollout)
+		category     Category
+		newRevision  bool
+		stepsChanged bool
+		paths        []string
+	}{
+		{
+			name: "no-op",
+			mutate: func(ro *v1alpha1.Rollout) {
+				ro.ResourceVersion = "2"
+				ro.Generation = 3
+				ro.Status.Phase = v1alpha1.RolloutPhaseHealthy
+			},
+			category: CategoryNone,
+		},
+		{
+			name:     "metadata",
+			mutate:   func(ro *v1alpha1.Rollout) { ro.Annotations = map[string]string{"owner": "me"} },
+			category: CategoryMetadata,
+			paths:    []string{"metadata.annotations"},
+		},
+		{
+			name:     "spec",
+			mutate:   func(ro *v1alpha1.Rollout) { ro.Spec.Replicas = int32Ptr(5) },
+			category: CategorySpec,
+			paths:    []string{"spec.replicas"},
+		},
+		{
+			name: "steps",
+			mutate: func(ro *v1alpha1.Rollout) {
+				ro.Spec.Strategy.Canary.Steps[2].SetWeight = int32Ptr(60)
+				ro.Labels["team"] = "b"
+			},
+			category:     CategorySteps,
+			stepsChanged: true,
+			paths:        []string{"metadata.labels.team", "spec.strategy.canary.steps[2].setWeight"},
+		},
+		{
+			name:         "canary removed",
+			mutate:       func(ro *v1alpha1.Rollout) { ro.Spec.Strategy.Canary = nil },
+			category:     CategorySteps,
+			stepsChanged: true,
+			paths:        []string{"spec.strategy.canary"},
+		},
+		{
+			name: "pod template",
+			mutate: func(ro *v1alpha1.Rollout) {
+				ro.Spec.Template.Spec.Containers[0].Image = "nginx:1.24"
+				ro.Spec.Template.Labels["app.kubernetes.io/version"] = "1.24"
+				ro.Spec.Strategy.Canary.Steps = append(ro.Spec.Strategy.Canary.Steps, v1alpha1.CanaryStep{SetWeight: int32Ptr(100)})
+			},
+			category:     CategoryPodTemplate,
+			newRevision:  true,
+			stepsChanged: true,
+			paths: []string{
+				"spec.strategy.canary.steps",
+				"spec.template.metadata.labels[app.kubernetes.io/version]",
+				"spec.template.spec.containers[0].image",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldRollout := newRollout()
+			newRollout := oldRollout.DeepCopy()
+			tt.mutate(newRollout)
+			result, err := Compare(oldRollout, newRollout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Category != tt.category {
+				t.Errorf("Category = %s, want %s", result.Category, tt.category)
+			}
+			if result.NewRevision != tt.newRevision || result.StepsChanged != tt.stepsChanged {
+				t.Errorf("NewRevision, StepsChanged = %v, %v, want %v, %v", result.NewRevision, result.StepsChanged, tt.newRevision, tt.stepsChanged)
+			}
+			if (result.StepChange != nil) != tt.stepsChanged {
+				t.Errorf("StepChange = %v, want it set only when the steps changed", result.StepChange)
+			}
+			var paths []string
+			for _, change := range result.Changes {
+				paths = append(paths, change.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.paths) {
+				t.Errorf("changed paths = %v, want %v", paths, tt.paths)
+			}
+		})
+	}
+}
+
+func TestCompareCanaryAdded(t *testing.T) {
+	oldRollout := newRollout()
+	oldRollout.Spec.Strategy.Canary = nil
+	oldRollout.Status.CurrentStepIndex = nil
+	result, err := Compare(oldRollout, newRollout())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Category != CategorySteps || !result.StepsChanged {
+		t.Errorf("Category, StepsChanged = %s, %v, want %s, true", result.Category, result.StepsChanged, CategorySteps)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Path != "spec.strategy.canary" || result.Changes[0].Category != CategorySteps {
+		t.Errorf("unexpected changes %v", result.Changes)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	oldRollout := newRollout()
+	newRollout := oldRollout.DeepCopy()
+	newRollout.Spec.Strategy.Canary.Steps[0].SetWeight = int32Ptr(10)
+	result, err := Compare(oldRollout, newRollout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := result.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Canary steps changed\n",
+		"Pod template hash: " + result.OldPodTemplateHash + " (unchanged)\n",
+		"Step hash: " + result.OldStepHash + " -> " + result.NewStepHash + "\n",
+		"Steps: ModifiedPast, the rollout restarts from step 0\n",
+		"  [steps] spec.strategy.canary.steps[0].setWeight: 20 -> 10\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteText() output does not contain %q:\n%s", want, b.String())
+		}
+	}
+
+	b.Reset()
+	result, _ = Compare(oldRollout, oldRollout)
+	if err := result.WriteText(&b); err != nil || b.String() != "No changes\n" {
+		t.Errorf("WriteText() = %q, %v, want No changes", b.String(), err)
+	}
+}
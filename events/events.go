@@ -0,0 +1,171 @@
+// Package events defines the reasons of the Kubernetes events recorded for Rollouts and a Recorder emitting
+// them with consistent messages.
+package events
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+const (
+	// RolloutUpdatedReason is recorded when the pod template of a rollout changes and a new revision starts
+	RolloutUpdatedReason = "RolloutUpdated"
+	// RolloutStepCompletedReason is recorded when a canary step is completed
+	RolloutStepCompletedReason = "RolloutStepCompleted"
+	// RolloutPausedReason is recorded when a rollout is paused
+	RolloutPausedReason = "RolloutPaused"
+	// RolloutResumedReason is recorded when a paused rollout is resumed
+	RolloutResumedReason = "RolloutResumed"
+	// RolloutAbortedReason is recorded when a rollout is aborted
+	RolloutAbortedReason = v1alpha1.RolloutAbortedReason
+	// RolloutRetriedReason is recorded when an aborted rollout is retried
+	RolloutRetriedReason = v1alpha1.RolloutRetriedReason
+	// RolloutTimedOutReason is recorded when a rollout exceeds its progress deadline
+	RolloutTimedOutReason = v1alpha1.RolloutTimedOutReason
+	// RolloutCompletedReason is recorded when a revision is fully promoted and becomes stable
+	RolloutCompletedReason = "RolloutCompleted"
+	// RolloutRestartedReason is recorded when the pods of a rollout are restarted for Spec.RestartAt
+	RolloutRestartedReason = "RolloutRestarted"
+	// ScalingReplicaSetReason is recorded when a ReplicaSet of a rollout is scaled
+	ScalingReplicaSetReason = "ScalingReplicaSet"
+
+	// ClusterCodeAnnotation is the annotation of the events holding the clusterCode of the rollout
+	ClusterCodeAnnotation = "rollout.argoproj.io/cluster-code"
+)
+
+// EventRecorder records Kubernetes events. It is the subset of record.EventRecorder used by the Recorder.
+type EventRecorder interface {
+	AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{})
+}
+
+// Recorder records the events of Rollouts. In multi-cluster setups, the clusterCode of the rollout is set
+// as ClusterCodeAnnotation and prefixes the message, it is omitted when empty.
+type Recorder struct {
+	recorder EventRecorder
+}
+
+// NewRecorder returns a Recorder recording the events with recorder, usually a record.EventRecorder.
+func NewRecorder(recorder EventRecorder) *Recorder {
+	return &Recorder{recorder: recorder}
+}
+
+// Eventf records an event for the rollout.
+func (r *Recorder) Eventf(clusterCode string, rollout *v1alpha1.Rollout, eventType, reason, messageFmt string, args ...interface{}) {
+	var annotations map[string]string
+	message := fmt.Sprintf(messageFmt, args...)
+	if clusterCode != "" {
+		annotations = map[string]string{ClusterCodeAnnotation: clusterCode}
+		message = fmt.Sprintf("[%s] %s", clusterCode, message)
+	}
+	r.recorder.AnnotatedEventf(rollout, annotations, eventType, reason, "%s", message)
+}
+
+// RolloutUpdated records that the pod template changed and the revision of the rollout started.
+func (r *Recorder) RolloutUpdated(clusterCode string, rollout *v1alpha1.Rollout) {
+	revision, _ := v1alpha1.GetRevision(rollout)
+	r.Eventf(clusterCode, rollout, corev1.EventTypeNormal, RolloutUpdatedReason,
+		"Rollout updated to revision %d", revision)
+}
+
+// StepCompleted records that the canary step at index is completed.
+func (r *Recorder) StepCompleted(clusterCode string, rollout *v1alpha1.Rollout, index int32) {
+	total := 0
+	description := "step"
+	if canary := rollout.Spec.Strategy.Canary; canary != nil {
+		total = len(canary.Steps)
+		if index >= 0 && int(index) < total {
+			description = describeStep(canary.Steps[index])
+		}
+	}
+	r.Eventf(clusterCode, rollout, corev1.EventTypeNormal, RolloutStepCompletedReason,
+		"Rollout step %d/%d completed (%s)", index+1, total, description)
+}
+
+// Paused records that the rollout is paused for reason.
+func (r *Recorder) Paused(clusterCode string, rollout *v1alpha1.Rollout, reason v1alpha1.PauseReason) {
+	r.Eventf(clusterCode, rollout, corev1.EventTypeNormal, RolloutPausedReason, "Rollout is paused (%s)", reason)
+}
+
+// Resumed records that the rollout is resumed.
+func (r *Recorder) Resumed(clusterCode string, rollout *v1alpha1.Rollout) {
+	r.Eventf(clusterCode, rollout, corev1.EventTypeNormal, RolloutResumedReason, "Rollout is resumed")
+}
+
+// Aborted records a warning that the rollout is aborted, message being the cause.
+func (r *Recorder) Aborted(clusterCode string, rollout *v1alpha1.Rollout, message string) {
+	r.Eventf(clusterCode, rollout, corev1.EventTypeWarning, RolloutAbortedReason, "Rollout aborted update: %s", message)
+}
+
+// Retried records that the aborted rollout is retried.
+func (r *Recorder) Retried(clusterCode string, rollout *v1alpha1.Rollout) {
+	r.Eventf(clusterCode, rollout, corev1.EventTypeNormal, RolloutRetriedReason, "Rollout is retried")
+}
+
+// TimedOut records a warning that the rollout exceeded its progress deadline.
+func (r *Recorder) TimedOut(clusterCode string, rollout *v1alpha1.Rollout) {
+	r.Eventf(clusterCode, rollout, corev1.EventTypeWarning, RolloutTimedOutReason,
+		"Rollout has not progressed for %d seconds", progressDeadlineSeconds(rollout))
+}
+
+// Completed records that the revision of the rollout is fully promoted.
+func (r *Recorder) Completed(clusterCode string, rollout *v1alpha1.Rollout) {
+	revision, _ := v1alpha1.GetRevision(rollout)
+	r.Eventf(clusterCode, rollout, corev1.EventTypeNormal, RolloutCompletedReason,
+		"Rollout completed update to revision %d (%s)", revision, rollout.Status.CurrentPodHash)
+}
+
+// Restarted records that the pods of the rollout are restarted.
+func (r *Recorder) Restarted(clusterCode string, rollout *v1alpha1.Rollout) {
+	if rollout.Spec.RestartAt == nil {
+		r.Eventf(clusterCode, rollout, corev1.EventTypeNormal, RolloutRestartedReason, "Rollout restarted pods")
+		return
+	}
+	r.Eventf(clusterCode, rollout, corev1.EventTypeNormal, RolloutRestartedReason,
+		"Rollout restarted pods created before %s", rollout.Spec.RestartAt.UTC().Format(time.RFC3339))
+}
+
+// ScalingReplicaSet records that the ReplicaSet of the rollout is scaled from oldReplicas to newReplicas.
+func (r *Recorder) ScalingReplicaSet(clusterCode string, rollout *v1alpha1.Rollout, rs *appsv1.ReplicaSet, oldReplicas, newReplicas int32) {
+	direction := "up"
+	if newReplicas < oldReplicas {
+		direction = "down"
+	}
+	revision, _ := v1alpha1.GetRevision(rs)
+	r.Eventf(clusterCode, rollout, corev1.EventTypeNormal, ScalingReplicaSetReason,
+		"Scaled %s ReplicaSet %s (revision %d) from %d to %d", direction, rs.Name, revision, oldReplicas, newReplicas)
+}
+
+func describeStep(step v1alpha1.CanaryStep) string {
+	switch {
+	case step.SetWeight != nil:
+		return fmt.Sprintf("setWeight: %d", *step.SetWeight)
+	case step.Pause != nil:
+		if step.Pause.Duration == nil {
+			return "pause"
+		}
+		return fmt.Sprintf("pause: %s", step.Pause.Duration.String())
+	case step.SetCanaryScale != nil:
+		scale := step.SetCanaryScale
+		if scale.Replicas != nil {
+			return fmt.Sprintf("setCanaryScale: %d replicas", *scale.Replicas)
+		}
+		if scale.Weight != nil {
+			return fmt.Sprintf("setCanaryScale: weight %d", *scale.Weight)
+		}
+		return "setCanaryScale"
+	}
+	return "step"
+}
+
+func progressDeadlineSeconds(rollout *v1alpha1.Rollout) int32 {
+	if rollout.Spec.ProgressDeadlineSeconds != nil {
+		return *rollout.Spec.ProgressDeadlineSeconds
+	}
+	return v1alpha1.DefaultProgressDeadlineSeconds
+}
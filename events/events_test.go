@@ -0,0 +1,116 @@
+package events_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/chamhaw/kubernetes-rollout-api/events"
+	"github.com/chamhaw/kubernetes-rollout-api/events/fake"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+// a record.EventRecorder can be wrapped by a Recorder
+var _ events.EventRecorder = record.EventRecorder(nil)
+
+func newRollout() *v1alpha1.Rollout {
+	weight := int32(20)
+	duration := intstr.FromString("1h")
+	restartAt := metav1.NewTime(time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC))
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "default",
+			Annotations: map[string]string{v1alpha1.RevisionAnnotation: "3"},
+		},
+		Spec: v1alpha1.RolloutSpec{
+			RestartAt: &restartAt,
+			Strategy: v1alpha1.RolloutStrategy{Canary: &v1alpha1.CanaryStrategy{
+				Steps: []v1alpha1.CanaryStep{{SetWeight: &weight}, {Pause: &v1alpha1.RolloutPause{Duration: &duration}}},
+			}},
+		},
+		Status: v1alpha1.RolloutStatus{CurrentPodHash: "abc123"},
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	ro := newRollout()
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:        "web-abc123",
+		Annotations: map[string]string{v1alpha1.RevisionAnnotation: "3"},
+	}}
+
+	for _, test := range []struct {
+		record   func(r *events.Recorder)
+		typ      string
+		reason   string
+		expected string
+	}{
+		{record: func(r *events.Recorder) { r.RolloutUpdated("dev", ro) },
+			typ: corev1.EventTypeNormal, reason: events.RolloutUpdatedReason, expected: "Rollout updated to revision 3"},
+		{record: func(r *events.Recorder) { r.StepCompleted("dev", ro, 0) },
+			typ: corev1.EventTypeNormal, reason: events.RolloutStepCompletedReason, expected: "Rollout step 1/2 completed (setWeight: 20)"},
+		{record: func(r *events.Recorder) { r.StepCompleted("dev", ro, 1) },
+			typ: corev1.EventTypeNormal, reason: events.RolloutStepCompletedReason, expected: "Rollout step 2/2 completed (pause: 1h)"},
+		{record: func(r *events.Recorder) { r.StepCompleted("dev", ro, -1) },
+			typ: corev1.EventTypeNormal, reason: events.RolloutStepCompletedReason, expected: "Rollout step 0/2 completed (step)"},
+		{record: func(r *events.Recorder) { r.Paused("dev", ro, v1alpha1.PauseReasonCanaryPauseStep) },
+			typ: corev1.EventTypeNormal, reason: events.RolloutPausedReason, expected: "Rollout is paused (CanaryPauseStep)"},
+		{record: func(r *events.Recorder) { r.Resumed("dev", ro) },
+			typ: corev1.EventTypeNormal, reason: events.RolloutResumedReason, expected: "Rollout is resumed"},
+		{record: func(r *events.Recorder) { r.Aborted("dev", ro, "analysis failed") },
+			typ: corev1.EventTypeWarning, reason: events.RolloutAbortedReason, expected: "Rollout aborted update: analysis failed"},
+		{record: func(r *events.Recorder) { r.Retried("dev", ro) },
+			typ: corev1.EventTypeNormal, reason: events.RolloutRetriedReason, expected: "Rollout is retried"},
+		{record: func(r *events.Recorder) { r.TimedOut("dev", ro) },
+			typ: corev1.EventTypeWarning, reason: events.RolloutTimedOutReason, expected: "Rollout has not progressed for 600 seconds"},
+		{record: func(r *events.Recorder) { r.Completed("dev", ro) },
+			typ: corev1.EventTypeNormal, reason: events.RolloutCompletedReason, expected: "Rollout completed update to revision 3 (abc123)"},
+		{record: func(r *events.Recorder) { r.Restarted("dev", ro) },
+			typ: corev1.EventTypeNormal, reason: events.RolloutRestartedReason, expected: "Rollout restarted pods created before 2022-11-21T10:00:00Z"},
+		{record: func(r *events.Recorder) { r.ScalingReplicaSet("dev", ro, rs, 3, 1) },
+			typ: corev1.EventTypeNormal, reason: events.ScalingReplicaSetReason, expected: "Scaled down ReplicaSet web-abc123 (revision 3) from 3 to 1"},
+	} {
+		fakeRecorder := fake.NewRecorder()
+		test.record(events.NewRecorder(fakeRecorder))
+		recorded := fakeRecorder.Events()
+		if len(recorded) != 1 {
+			t.Fatalf("%s: expected one event, got %v", test.reason, recorded)
+		}
+		event := recorded[0]
+		if event.Object != ro || event.Type != test.typ || event.Reason != test.reason {
+			t.Errorf("%s: unexpected event %v", test.reason, event)
+		}
+		if event.Message != "[dev] "+test.expected {
+			t.Errorf("%s: expected message %q, got %q", test.reason, "[dev] "+test.expected, event.Message)
+		}
+		if event.Annotations[events.ClusterCodeAnnotation] != "dev" {
+			t.Errorf("%s: expected the clusterCode annotation, got %v", test.reason, event.Annotations)
+		}
+	}
+}
+
+func TestRecorderWithoutClusterCode(t *testing.T) {
+	fakeRecorder := fake.NewRecorder()
+	recorder := events.NewRecorder(fakeRecorder)
+	recorder.Eventf("", newRollout(), corev1.EventTypeNormal, "Custom", "value %d%%", 50)
+	recorder.Resumed("", newRollout())
+
+	recorded := fakeRecorder.Events()
+	if len(recorded) != 2 || recorded[0].Message != "value 50%" || recorded[0].Annotations != nil {
+		t.Errorf("unexpected events %v", recorded)
+	}
+	if reasons := fakeRecorder.Reasons(); !reflect.DeepEqual(reasons, []string{"Custom", events.RolloutResumedReason}) {
+		t.Errorf("unexpected reasons %v", reasons)
+	}
+	fakeRecorder.Reset()
+	if len(fakeRecorder.Events()) != 0 {
+		t.Errorf("expected no events after a reset")
+	}
+}
@@ -0,0 +1,71 @@
+// Package fake provides an in-memory EventRecorder keeping the recorded events, to be used in tests.
+package fake
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/chamhaw/kubernetes-rollout-api/events"
+)
+
+// Event is a recorded event.
+type Event struct {
+	Object      runtime.Object
+	Annotations map[string]string
+	Type        string
+	Reason      string
+	Message     string
+}
+
+// Recorder is an EventRecorder keeping the recorded events in memory. It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+var _ events.EventRecorder = &Recorder{}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// AnnotatedEventf records the event.
+func (r *Recorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, Event{
+		Object:      object,
+		Annotations: annotations,
+		Type:        eventtype,
+		Reason:      reason,
+		Message:     fmt.Sprintf(messageFmt, args...),
+	})
+}
+
+// Events returns the recorded events, oldest first.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// Reasons returns the reasons of the recorded events, oldest first.
+func (r *Recorder) Reasons() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reasons := make([]string, 0, len(r.events))
+	for _, event := range r.events {
+		reasons = append(reasons, event.Reason)
+	}
+	return reasons
+}
+
+// Reset discards the recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
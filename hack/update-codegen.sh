@@ -0,0 +1,46 @@
+#!/usr/bin/env bash
+# Regenerates the apply configurations of the Rollout types in applyconfiguration/. Set APPLYCONFIGURATION_GEN
+# to use an already built applyconfiguration-gen instead of installing it.
+#
+# No OpenAPI model of the Rollout types is generated, so the schema in applyconfiguration/internal has no Rollout
+# types and the apply configurations have no Extract functions: extraction is unsupported.
+set -o errexit
+set -o nounset
+set -o pipefail
+
+MODULE=github.com/chamhaw/kubernetes-rollout-api
+ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+# the generator is of the same version as the k8s.io dependencies of the module
+CODEGEN_VERSION=${CODEGEN_VERSION:-$(cd "${ROOT}" && go list -m -f '{{.Version}}' k8s.io/apimachinery)}
+OUTPUT_BASE=$(mktemp -d)
+trap 'rm -rf "${OUTPUT_BASE}"' EXIT
+
+APPLYCONFIGURATION_GEN=${APPLYCONFIGURATION_GEN:-}
+if [[ -z "${APPLYCONFIGURATION_GEN}" ]]; then
+  GOBIN="${OUTPUT_BASE}/bin" go install "k8s.io/code-generator/cmd/applyconfiguration-gen@${CODEGEN_VERSION}"
+  APPLYCONFIGURATION_GEN="${OUTPUT_BASE}/bin/applyconfiguration-gen"
+fi
+
+# TypeMeta, ObjectMeta and OwnerReference are mapped to client-go by default
+EXTERNAL="k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector:k8s.io/client-go/applyconfigurations/meta/v1"
+EXTERNAL+=",k8s.io/api/core/v1.PodTemplateSpec:k8s.io/client-go/applyconfigurations/core/v1"
+
+# the sources of the module have no license header
+cd "${ROOT}"
+"${APPLYCONFIGURATION_GEN}" \
+  --go-header-file /dev/null \
+  --input-dirs "${MODULE}/v1alpha1" \
+  --external-applyconfigurations "${EXTERNAL}" \
+  --output-package "${MODULE}/applyconfiguration" \
+  --output-base "${OUTPUT_BASE}"
+
+# the generator names the group directory after the parent directory of the types, kubernetes-rollout-api,
+# the versions are moved directly under applyconfiguration/
+GENERATED="${OUTPUT_BASE}/${MODULE}/applyconfiguration"
+mv "${GENERATED}/kubernetes-rollout-api/v1alpha1" "${GENERATED}/v1alpha1"
+rmdir "${GENERATED}/kubernetes-rollout-api"
+sed -i.bak -e "s|${MODULE}/applyconfiguration/kubernetes-rollout-api/|${MODULE}/applyconfiguration/|" "${GENERATED}/utils.go"
+rm "${GENERATED}/utils.go.bak"
+
+rm -rf applyconfiguration
+cp -r "${GENERATED}" applyconfiguration
@@ -0,0 +1,289 @@
+// Package lint reports problems in Rollout manifests that pass validation but are likely mistakes.
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+// Severity of a problem
+type Severity string
+
+const (
+	// SeverityError is a problem that breaks the rollout
+	SeverityError Severity = "error"
+	// SeverityWarning is a problem that is most likely a mistake
+	SeverityWarning Severity = "warning"
+	// SeverityInfo is a remark that does not need to be fixed
+	SeverityInfo Severity = "info"
+)
+
+// Rule identifies the check that reported a problem
+type Rule string
+
+const (
+	// RuleNoPauseBeforeFull reports canary steps reaching 100% without any pause
+	RuleNoPauseBeforeFull Rule = "no-pause-before-full"
+	// RuleDecreasingWeight reports a SetWeight lower than the one of a previous step
+	RuleDecreasingWeight Rule = "decreasing-weight"
+	// RuleMissingResourceRequests reports containers without cpu or memory requests
+	RuleMissingResourceRequests Rule = "missing-resource-requests"
+	// RuleInvalidPauseDuration reports pause durations that cannot be parsed
+	RuleInvalidPauseDuration Rule = "invalid-pause-duration"
+	// RuleReservedLabel reports selectors, pod labels and ephemeral metadata using DefaultRolloutUniqueLabelKey
+	RuleReservedLabel Rule = "reserved-label"
+	// RuleMetadataOverridesSelector reports canary or stable metadata setting labels of the selector
+	RuleMetadataOverridesSelector Rule = "metadata-overrides-selector"
+)
+
+// Problem is a problem found in a rollout.
+type Problem struct {
+	// Source is the file the rollout was read from, empty for rollouts linted directly
+	Source string `json:"source,omitempty"`
+	// Rollout is the namespace/name of the rollout
+	Rollout  string   `json:"rollout"`
+	Rule     Rule     `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Field is the path of the field with the problem
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	prefix := p.Rollout
+	if p.Source != "" {
+		prefix = p.Source + ": " + prefix
+	}
+	return fmt.Sprintf("%s: %s: %s: %s (%s)", prefix, p.Severity, p.Field, p.Message, p.Rule)
+}
+
+// HasErrors returns true if one of the problems has the error severity.
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint returns the problems of the rollout.
+func Lint(rollout *v1alpha1.Rollout) []Problem {
+	l := linter{rollout: rollout}
+	specPath := field.NewPath("spec")
+	l.lintSteps(specPath.Child("strategy", "canary", "steps"))
+	l.lintResources(specPath.Child("template", "spec"))
+	l.lintLabels(specPath)
+	return l.problems
+}
+
+type linter struct {
+	rollout  *v1alpha1.Rollout
+	problems []Problem
+}
+
+func (l *linter) add(rule Rule, severity Severity, fldPath *field.Path, format string, args ...interface{}) {
+	l.addProblem(rule, severity, fldPath.String(), fmt.Sprintf(format, args...))
+}
+
+func (l *linter) addProblem(rule Rule, severity Severity, fieldPath, message string) {
+	name := l.rollout.Name
+	if l.rollout.Namespace != "" {
+		name = l.rollout.Namespace + "/" + name
+	}
+	l.problems = append(l.problems, Problem{
+		Rollout:  name,
+		Rule:     rule,
+		Severity: severity,
+		Field:    fieldPath,
+		Message:  message,
+	})
+}
+
+func (l *linter) lintSteps(fldPath *field.Path) {
+	canary := l.rollout.Spec.Strategy.Canary
+	if canary == nil || len(canary.Steps) == 0 {
+		return
+	}
+
+	paused := false
+	full := -1
+	var lastWeight *int32
+	lastWeightIndex := 0
+	for i, step := range canary.Steps {
+		stepPath := fldPath.Index(i)
+		if step.Pause != nil {
+			paused = true
+			if step.Pause.DurationSeconds() < 0 {
+				l.add(RuleInvalidPauseDuration, SeverityError, stepPath.Child("pause", "duration"),
+					"%q is not a valid duration, the pause ends as soon as it starts", step.Pause.Duration.String())
+			}
+		}
+		if step.SetWeight == nil {
+			continue
+		}
+		if lastWeight != nil && *step.SetWeight < *lastWeight {
+			l.add(RuleDecreasingWeight, SeverityWarning, stepPath.Child("setWeight"),
+				"setWeight %d is lower than %d set by step %d, traffic moves back to the stable version",
+				*step.SetWeight, *lastWeight, lastWeightIndex)
+		}
+		lastWeight, lastWeightIndex = step.SetWeight, i
+		if *step.SetWeight >= 100 && full < 0 {
+			full = i
+			if !paused {
+				l.add(RuleNoPauseBeforeFull, SeverityWarning, stepPath.Child("setWeight"),
+					"setWeight reaches 100 without a pause step before it")
+			}
+		}
+	}
+	if full < 0 && !paused {
+		l.add(RuleNoPauseBeforeFull, SeverityWarning, fldPath,
+			"no pause step before the rollout completes at 100%%")
+	}
+}
+
+func (l *linter) lintResources(fldPath *field.Path) {
+	spec := &l.rollout.Spec.Template.Spec
+	lint := func(containers []corev1.Container, containersPath *field.Path) {
+		for i, c := range containers {
+			var missing []string
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				if _, ok := c.Resources.Requests[name]; !ok {
+					missing = append(missing, string(name))
+				}
+			}
+			if len(missing) == 0 {
+				continue
+			}
+			resourcesPath := containersPath.Index(i).Child("resources", "requests")
+			defaulted := true
+			for _, name := range missing {
+				if _, ok := c.Resources.Limits[corev1.ResourceName(name)]; !ok {
+					defaulted = false
+				}
+			}
+			if defaulted {
+				l.add(RuleMissingResourceRequests, SeverityInfo, resourcesPath,
+					"container %q has no %v requests, they default to its limits", c.Name, missing)
+			} else {
+				l.add(RuleMissingResourceRequests, SeverityWarning, resourcesPath,
+					"container %q has no %v requests", c.Name, missing)
+			}
+		}
+	}
+	lint(spec.InitContainers, fldPath.Child("initContainers"))
+	lint(spec.Containers, fldPath.Child("containers"))
+}
+
+func (l *linter) lintLabels(fldPath *field.Path) {
+	if selector := l.rollout.Spec.Selector; selector != nil {
+		selectorPath := fldPath.Child("selector")
+		if _, ok := selector.MatchLabels[v1alpha1.DefaultRolloutUniqueLabelKey]; ok {
+			l.add(RuleReservedLabel, SeverityError, selectorPath.Child("matchLabels").Key(v1alpha1.DefaultRolloutUniqueLabelKey),
+				"the selector must not use the label set by the controller on each ReplicaSet")
+		}
+		for i, expr := range selector.MatchExpressions {
+			if expr.Key == v1alpha1.DefaultRolloutUniqueLabelKey {
+				l.add(RuleReservedLabel, SeverityError, selectorPath.Child("matchExpressions").Index(i).Child("key"),
+					"the selector must not use the label set by the controller on each ReplicaSet")
+			}
+		}
+	}
+	if _, ok := l.rollout.Spec.Template.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]; ok {
+		l.add(RuleReservedLabel, SeverityError, fldPath.Child("template", "metadata", "labels").Key(v1alpha1.DefaultRolloutUniqueLabelKey),
+			"the label is overwritten by the controller on each ReplicaSet")
+	}
+
+	reservedKey := field.NewPath("labels").Key(v1alpha1.DefaultRolloutUniqueLabelKey).String()
+	for _, err := range v1alpha1.ValidateEphemeralMetadata(l.rollout, fldPath.Child("strategy", "canary")) {
+		rule := RuleMetadataOverridesSelector
+		if strings.HasSuffix(err.Field, "."+reservedKey) {
+			rule = RuleReservedLabel
+		}
+		l.addProblem(rule, SeverityError, err.Field, err.Detail)
+	}
+}
+
+// LintReader decodes the Rollout and RolloutList documents of a YAML or JSON stream and returns their
+// problems. Documents of other kinds or groups are ignored. source is set on the returned problems.
+func LintReader(r io.Reader, source string) ([]Problem, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	var problems []Problem
+	for doc := 1; ; doc++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return problems, nil
+			}
+			return nil, fmt.Errorf("%s: document %d: %w", source, doc, err)
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+			continue
+		}
+		rollouts, err := decodeRollouts(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", source, doc, err)
+		}
+		for i := range rollouts {
+			for _, p := range Lint(&rollouts[i]) {
+				p.Source = source
+				problems = append(problems, p)
+			}
+		}
+	}
+}
+
+func decodeRollouts(raw []byte) ([]v1alpha1.Rollout, error) {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.GroupVersionKind().Group != v1alpha1.GroupName {
+		return nil, nil
+	}
+	switch typeMeta.Kind {
+	case v1alpha1.RolloutKind:
+		var ro v1alpha1.Rollout
+		if err := json.Unmarshal(raw, &ro); err != nil {
+			return nil, err
+		}
+		return []v1alpha1.Rollout{ro}, nil
+	case v1alpha1.RolloutKind + "List":
+		var list v1alpha1.RolloutList
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+	return nil, nil
+}
+
+// LintFiles lints the rollouts of the files, in the order given.
+func LintFiles(paths ...string) ([]Problem, error) {
+	var problems []Problem
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		fileProblems, err := LintReader(f, path)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, fileProblems...)
+	}
+	return problems, nil
+}
@@ -0,0 +1,190 @@
+package lint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func newRollout(steps ...v1alpha1.CanaryStep) *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					}},
+				}}},
+			},
+			Strategy: v1alpha1.RolloutStrategy{Canary: &v1alpha1.CanaryStrategy{Steps: steps}},
+		},
+	}
+}
+
+func pause(duration string) v1alpha1.CanaryStep {
+	if duration == "" {
+		return v1alpha1.CanaryStep{Pause: &v1alpha1.RolloutPause{}}
+	}
+	d := intstr.Parse(duration)
+	return v1alpha1.CanaryStep{Pause: &v1alpha1.RolloutPause{Duration: &d}}
+}
+
+func weight(w int32) v1alpha1.CanaryStep {
+	return v1alpha1.CanaryStep{SetWeight: int32Ptr(w)}
+}
+
+type finding struct {
+	Rule     Rule
+	Severity Severity
+	Field    string
+}
+
+func findings(problems []Problem) []finding {
+	var result []finding
+	for _, p := range problems {
+		result = append(result, finding{p.Rule, p.Severity, p.Field})
+	}
+	return result
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		rollout func() *v1alpha1.Rollout
+		want    []finding
+	}{
+		{
+			name:    "valid",
+			rollout: func() *v1alpha1.Rollout { return newRollout(weight(20), pause("5m"), weight(100)) },
+		},
+		{
+			name:    "no steps",
+			rollout: func() *v1alpha1.Rollout { return newRollout() },
+		},
+		{
+			name:    "no pause before 100",
+			rollout: func() *v1alpha1.Rollout { return newRollout(weight(20), weight(100), pause("")) },
+			want:    []finding{{RuleNoPauseBeforeFull, SeverityWarning, "spec.strategy.canary.steps[1].setWeight"}},
+		},
+		{
+			name:    "no pause at all",
+			rollout: func() *v1alpha1.Rollout { return newRollout(weight(20), weight(50)) },
+			want:    []finding{{RuleNoPauseBeforeFull, SeverityWarning, "spec.strategy.canary.steps"}},
+		},
+		{
+			name:    "decreasing weight",
+			rollout: func() *v1alpha1.Rollout { return newRollout(weight(50), pause(""), weight(20)) },
+			want:    []finding{{RuleDecreasingWeight, SeverityWarning, "spec.strategy.canary.steps[2].setWeight"}},
+		},
+		{
+			name:    "invalid pause duration",
+			rollout: func() *v1alpha1.Rollout { return newRollout(weight(20), pause("10x"), pause("30")) },
+			want:    []finding{{RuleInvalidPauseDuration, SeverityError, "spec.strategy.canary.steps[1].pause.duration"}},
+		},
+		{
+			name: "missing requests",
+			rollout: func() *v1alpha1.Rollout {
+				ro := newRollout()
+				ro.Spec.Template.Spec.InitContainers = []corev1.Container{{
+					Name:      "init",
+					Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")}},
+				}}
+				delete(ro.Spec.Template.Spec.Containers[0].Resources.Requests, corev1.ResourceMemory)
+				return ro
+			},
+			want: []finding{
+				{RuleMissingResourceRequests, SeverityInfo, "spec.template.spec.initContainers[0].resources.requests"},
+				{RuleMissingResourceRequests, SeverityWarning, "spec.template.spec.containers[0].resources.requests"},
+			},
+		},
+		{
+			name: "reserved label",
+			rollout: func() *v1alpha1.Rollout {
+				ro := newRollout()
+				ro.Spec.Selector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: v1alpha1.DefaultRolloutUniqueLabelKey, Operator: metav1.LabelSelectorOpExists}}
+				ro.Spec.Template.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] = "abc"
+				return ro
+			},
+			want: []finding{
+				{RuleReservedLabel, SeverityError, "spec.selector.matchExpressions[0].key"},
+				{RuleReservedLabel, SeverityError, "spec.template.metadata.labels[rollouts-pod-template-hash]"},
+			},
+		},
+		{
+			name: "metadata overrides selector",
+			rollout: func() *v1alpha1.Rollout {
+				ro := newRollout()
+				ro.Spec.Strategy.Canary.StableMetadata = &v1alpha1.PodTemplateMetadata{Labels: map[string]string{"app": "stable", "role": "stable"}}
+				return ro
+			},
+			want: []finding{{RuleMetadataOverridesSelector, SeverityError, "spec.strategy.canary.stableMetadata.labels[app]"}},
+		},
+		{
+			name: "metadata uses reserved label",
+			rollout: func() *v1alpha1.Rollout {
+				ro := newRollout()
+				ro.Spec.Strategy.Canary.CanaryMetadata = &v1alpha1.PodTemplateMetadata{Labels: map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "x"}}
+				return ro
+			},
+			want: []finding{{RuleReservedLabel, SeverityError, "spec.strategy.canary.canaryMetadata.labels[rollouts-pod-template-hash]"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findings(Lint(tt.rollout()))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintFiles(t *testing.T) {
+	problems, err := LintFiles("testdata/rollouts.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []finding{
+		{RuleDecreasingWeight, SeverityWarning, "spec.strategy.canary.steps[1].setWeight"},
+		{RuleInvalidPauseDuration, SeverityError, "spec.strategy.canary.steps[2].pause.duration"},
+		{RuleMissingResourceRequests, SeverityWarning, "spec.template.spec.containers[0].resources.requests"},
+		{RuleReservedLabel, SeverityError, "spec.selector.matchLabels[rollouts-pod-template-hash]"},
+		{RuleMetadataOverridesSelector, SeverityError, "spec.strategy.canary.canaryMetadata.labels[app]"},
+	}
+	if got := findings(problems); !reflect.DeepEqual(got, want) {
+		t.Errorf("LintFiles() = %v, want %v", got, want)
+	}
+	for _, p := range problems {
+		if p.Source != "testdata/rollouts.yaml" || p.Rollout != "apps/api" {
+			t.Errorf("unexpected problem %s", p)
+		}
+	}
+	if !HasErrors(problems) {
+		t.Errorf("HasErrors() = false, want true")
+	}
+
+	if _, err := LintReader(strings.NewReader("kind: Rollout\nspec: [\n"), "broken.yaml"); err == nil || !strings.Contains(err.Error(), "broken.yaml: document 1") {
+		t.Errorf("LintReader() error = %v, want an error for the document", err)
+	}
+	other, err := LintReader(strings.NewReader("apiVersion: example.com/v1\nkind: Rollout\nspec:\n  selector:\n    matchLabels:\n      rollouts-pod-template-hash: x\n"), "other.yaml")
+	if err != nil || len(other) != 0 {
+		t.Errorf("LintReader() = %v, %v, want rollouts of other groups to be ignored", other, err)
+	}
+	if _, err := LintFiles("testdata/missing.yaml"); err == nil {
+		t.Errorf("LintFiles() expected an error for a missing file")
+	}
+}
@@ -0,0 +1,160 @@
+// Package metrics exports Prometheus metrics describing the Rollouts of RolloutListers, one lister per clusterCode.
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+const (
+	// StrategyCanary is the strategy label of rollouts with a canary strategy
+	StrategyCanary = "canary"
+	// StrategyNone is the strategy label of rollouts without a strategy
+	StrategyNone = "none"
+)
+
+var rolloutLabels = []string{"cluster_code", "namespace", "name"}
+
+var (
+	upDesc = prometheus.NewDesc("rollout_collector_up", "Whether the rollouts of a cluster were listed, 0 when the "+
+		"lister failed and the metrics of the cluster are missing.", []string{"cluster_code"}, nil)
+	infoDesc = prometheus.NewDesc("rollout_info", "Information about a rollout.",
+		append(rolloutLabels, "strategy"), nil)
+	phaseDesc = prometheus.NewDesc("rollout_phase", "Phase of a rollout, 1 for the current phase and 0 for the others, "+
+		"all 0 when the phase is not set yet.",
+		append(rolloutLabels, "phase"), nil)
+	desiredReplicasDesc = prometheus.NewDesc("rollout_replicas_desired", "Number of desired pods of a rollout.",
+		rolloutLabels, nil)
+	availableReplicasDesc = prometheus.NewDesc("rollout_replicas_available", "Number of available pods of a rollout.",
+		rolloutLabels, nil)
+	updatedReplicasDesc = prometheus.NewDesc("rollout_replicas_updated", "Number of pods of a rollout running the current revision.",
+		rolloutLabels, nil)
+	currentStepDesc = prometheus.NewDesc("rollout_current_step", "Index of the current canary step of a rollout, "+
+		"equal to the number of steps once they are all completed.", rolloutLabels, nil)
+	pausedSecondsDesc = prometheus.NewDesc("rollout_paused_seconds", "Time in seconds since a rollout was paused by a "+
+		"pause condition, 0 when it is not paused.", rolloutLabels, nil)
+)
+
+// phases are the phases exported by rollout_phase. A rollout without a phase, not yet reconciled, has no
+// current phase: all its rollout_phase series are 0.
+var phases = []v1alpha1.RolloutPhase{
+	v1alpha1.RolloutPhaseHealthy,
+	v1alpha1.RolloutPhaseDegraded,
+	v1alpha1.RolloutPhaseProgressing,
+	v1alpha1.RolloutPhasePaused,
+}
+
+// Collector is a prometheus.Collector exporting the metrics of the Rollouts listed by a RolloutLister
+// per clusterCode. The metrics are computed from the listers on every scrape.
+type Collector struct {
+	listers map[string]v1alpha1.RolloutLister
+	now     func() time.Time
+}
+
+var _ prometheus.Collector = &Collector{}
+
+// NewCollector returns a Collector over the listers, indexed by clusterCode.
+func NewCollector(listers map[string]v1alpha1.RolloutLister) *Collector {
+	return &Collector{listers: listers, now: time.Now}
+}
+
+// Register registers a Collector over the listers on registerer.
+func Register(registerer prometheus.Registerer, listers map[string]v1alpha1.RolloutLister) (*Collector, error) {
+	c := NewCollector(listers)
+	if err := registerer.Register(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{upDesc, infoDesc, phaseDesc, desiredReplicasDesc, availableReplicasDesc,
+		updatedReplicasDesc, currentStepDesc, pausedSecondsDesc} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector. A lister failing to list its rollouts does not fail the scrape:
+// its cluster is skipped and reported by rollout_collector_up.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	now := c.now()
+	clusterCodes := make([]string, 0, len(c.listers))
+	for clusterCode := range c.listers {
+		clusterCodes = append(clusterCodes, clusterCode)
+	}
+	sort.Strings(clusterCodes)
+	for _, clusterCode := range clusterCodes {
+		rollouts, err := c.listers[clusterCode].List(labels.Everything())
+		up := 1.0
+		if err != nil {
+			up = 0
+		}
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up, clusterCode)
+		if err != nil {
+			continue
+		}
+		for _, ro := range rollouts {
+			collectRollout(ch, clusterCode, ro, now)
+		}
+	}
+}
+
+func collectRollout(ch chan<- prometheus.Metric, clusterCode string, ro *v1alpha1.Rollout, now time.Time) {
+	values := []string{clusterCode, ro.Namespace, ro.Name}
+	gauge := func(desc *prometheus.Desc, value float64, extra ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, append(values, extra...)...)
+	}
+
+	strategy := StrategyNone
+	if ro.Spec.Strategy.Canary != nil {
+		strategy = StrategyCanary
+	}
+	gauge(infoDesc, 1, strategy)
+
+	for _, phase := range phases {
+		value := 0.0
+		if ro.Status.Phase == phase {
+			value = 1
+		}
+		gauge(phaseDesc, value, string(phase))
+	}
+
+	desired := v1alpha1.DefaultReplicas
+	if ro.Spec.Replicas != nil {
+		desired = *ro.Spec.Replicas
+	}
+	gauge(desiredReplicasDesc, float64(desired))
+	gauge(availableReplicasDesc, float64(ro.Status.AvailableReplicas))
+	gauge(updatedReplicasDesc, float64(ro.Status.UpdatedReplicas))
+
+	if canary := ro.Spec.Strategy.Canary; canary != nil && len(canary.Steps) > 0 {
+		current := int32(0)
+		if _, index := v1alpha1.GetCurrentCanaryStep(ro); index != nil {
+			current = *index
+		}
+		gauge(currentStepDesc, float64(current))
+	}
+
+	gauge(pausedSecondsDesc, pausedSeconds(ro, now))
+}
+
+// pausedSeconds returns the time since the oldest pause condition of the rollout started.
+func pausedSeconds(ro *v1alpha1.Rollout, now time.Time) float64 {
+	var since *time.Time
+	for i := range ro.Status.PauseConditions {
+		start := ro.Status.PauseConditions[i].StartTime.Time
+		if since == nil || start.Before(*since) {
+			since = &start
+		}
+	}
+	if since == nil || now.Before(*since) {
+		return 0
+	}
+	return now.Sub(*since).Seconds()
+}
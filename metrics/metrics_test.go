@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1/fake"
+)
+
+var now = time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+
+func newRollout(name string, replicas int32, phase v1alpha1.RolloutPhase) *v1alpha1.Rollout {
+	weight := int32(50)
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+		Spec: v1alpha1.RolloutSpec{
+			Replicas: &replicas,
+			Strategy: v1alpha1.RolloutStrategy{Canary: &v1alpha1.CanaryStrategy{
+				Steps: []v1alpha1.CanaryStep{{SetWeight: &weight}, {Pause: &v1alpha1.RolloutPause{}}},
+			}},
+		},
+		Status: v1alpha1.RolloutStatus{
+			Phase:             phase,
+			AvailableReplicas: replicas,
+			UpdatedReplicas:   replicas,
+		},
+	}
+}
+
+func newListers() map[string]v1alpha1.RolloutLister {
+	step := int32(1)
+	paused := newRollout("web", 4, v1alpha1.RolloutPhasePaused)
+	paused.Status.UpdatedReplicas = 2
+	paused.Status.CurrentStepIndex = &step
+	paused.Status.PauseConditions = []v1alpha1.PauseCondition{
+		{Reason: v1alpha1.PauseReasonCanaryPauseStep, StartTime: metav1.NewTime(now.Add(-90 * time.Second))},
+	}
+
+	completed := int32(2)
+	healthy := newRollout("web", 3, v1alpha1.RolloutPhaseHealthy)
+	healthy.Status.CurrentStepIndex = &completed
+
+	noStrategy := newRollout("worker", 1, v1alpha1.RolloutPhaseProgressing)
+	noStrategy.Spec.Replicas = nil
+	noStrategy.Spec.Strategy.Canary = nil
+
+	dev := fake.NewClientset("dev", paused)
+	prod := fake.NewClientset("prod", healthy, noStrategy)
+	return map[string]v1alpha1.RolloutLister{"dev": dev.Lister("dev"), "prod": prod.Lister("prod")}
+}
+
+func TestCollector(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	c, err := Register(registry, newListers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.now = func() time.Time { return now }
+
+	expected, err := os.Open("testdata/rollouts.prom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer expected.Close()
+	if err := testutil.GatherAndCompare(registry, expected); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := Register(registry, newListers()); err == nil {
+		t.Errorf("expected an error registering a second collector")
+	}
+}
+
+type failingLister struct {
+	v1alpha1.RolloutLister
+}
+
+func (failingLister) List(selector labels.Selector) ([]*v1alpha1.Rollout, error) {
+	return nil, errors.New("cache not synced")
+}
+
+func TestCollectorListError(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	listers := newListers()
+	listers["dev"] = failingLister{}
+	if _, err := Register(registry, listers); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP rollout_collector_up Whether the rollouts of a cluster were listed, 0 when the lister failed and the metrics of the cluster are missing.
+# TYPE rollout_collector_up gauge
+rollout_collector_up{cluster_code="dev"} 0
+rollout_collector_up{cluster_code="prod"} 1
+# HELP rollout_info Information about a rollout.
+# TYPE rollout_info gauge
+rollout_info{cluster_code="prod",name="web",namespace="apps",strategy="canary"} 1
+rollout_info{cluster_code="prod",name="worker",namespace="apps",strategy="none"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "rollout_collector_up", "rollout_info"); err != nil {
+		t.Errorf("expected the failing cluster to be skipped: %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+// Package tree renders the status of a Rollout, its steps, ReplicaSets and pods as a text tree.
+package tree
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+// Step markers
+const (
+	StepCompleted = "✓"
+	StepCurrent   = "•"
+	StepPending   = "○"
+)
+
+const (
+	branch     = "├── "
+	lastBranch = "└── "
+	indent     = "│   "
+	lastIndent = "    "
+)
+
+// Render writes the tree of the rollout. The ReplicaSets and pods are matched to the rollout through the
+// pod template hash label; now is used to compute ages and pause deadlines.
+func Render(w io.Writer, rollout *v1alpha1.Rollout, rss []*appsv1.ReplicaSet, pods []*corev1.Pod, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	renderSummary(tw, rollout)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	renderSteps(&b, rollout, now)
+	renderRevisions(&b, rollout, rss, pods, now)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func renderSummary(w io.Writer, rollout *v1alpha1.Rollout) {
+	fmt.Fprintf(w, "Name:\t%s\n", rollout.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", rollout.Namespace)
+	phase := string(rollout.Status.Phase)
+	if phase == "" {
+		phase = "Unknown"
+	}
+	fmt.Fprintf(w, "Status:\t%s\n", phase)
+	if rollout.Status.Message != "" {
+		fmt.Fprintf(w, "Message:\t%s\n", rollout.Status.Message)
+	}
+	if canary := rollout.Spec.Strategy.Canary; canary != nil {
+		fmt.Fprintf(w, "Strategy:\tCanary\n")
+		if len(canary.Steps) > 0 {
+			_, index := v1alpha1.GetCurrentCanaryStep(rollout)
+			fmt.Fprintf(w, "  Step:\t%d/%d\n", currentIndex(index), len(canary.Steps))
+		}
+		fmt.Fprintf(w, "  SetWeight:\t%d\n", v1alpha1.GetCurrentSetWeight(rollout))
+	}
+	desired := v1alpha1.DefaultReplicas
+	if rollout.Spec.Replicas != nil {
+		desired = *rollout.Spec.Replicas
+	}
+	fmt.Fprintf(w, "Replicas:\n")
+	fmt.Fprintf(w, "  Desired:\t%d\n", desired)
+	fmt.Fprintf(w, "  Current:\t%d\n", rollout.Status.Replicas)
+	fmt.Fprintf(w, "  Updated:\t%d\n", rollout.Status.UpdatedReplicas)
+	fmt.Fprintf(w, "  Ready:\t%d\n", rollout.Status.ReadyReplicas)
+	fmt.Fprintf(w, "  Available:\t%d\n", rollout.Status.AvailableReplicas)
+}
+
+func currentIndex(index *int32) int32 {
+	if index == nil {
+		return 0
+	}
+	return *index
+}
+
+func renderSteps(b *strings.Builder, rollout *v1alpha1.Rollout, now time.Time) {
+	canary := rollout.Spec.Strategy.Canary
+	if canary == nil || len(canary.Steps) == 0 {
+		return
+	}
+	b.WriteString("Steps:\n")
+	_, index := v1alpha1.GetCurrentCanaryStep(rollout)
+	current := int(currentIndex(index))
+	for i, step := range canary.Steps {
+		marker := StepPending
+		switch {
+		case i < current:
+			marker = StepCompleted
+		case i == current && !rollout.Status.Abort:
+			marker = StepCurrent
+		}
+		prefix := branch
+		if i == len(canary.Steps)-1 {
+			prefix = lastBranch
+		}
+		fmt.Fprintf(b, "%s%s %d: %s\n", prefix, marker, i, describeStep(rollout, step, i == current, now))
+	}
+}
+
+func describeStep(rollout *v1alpha1.Rollout, step v1alpha1.CanaryStep, current bool, now time.Time) string {
+	var parts []string
+	if step.SetWeight != nil {
+		parts = append(parts, fmt.Sprintf("setWeight %d", *step.SetWeight))
+	}
+	if scale := step.SetCanaryScale; scale != nil {
+		switch {
+		case scale.Replicas != nil:
+			parts = append(parts, fmt.Sprintf("setCanaryScale replicas %d", *scale.Replicas))
+		case scale.Weight != nil:
+			parts = append(parts, fmt.Sprintf("setCanaryScale weight %d", *scale.Weight))
+		default:
+			parts = append(parts, "setCanaryScale")
+		}
+	}
+	if step.Pause != nil {
+		parts = append(parts, describePause(rollout, step.Pause, current, now))
+	}
+	if len(parts) == 0 {
+		return "empty step"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describePause(rollout *v1alpha1.Rollout, pause *v1alpha1.RolloutPause, current bool, now time.Time) string {
+	if pause.Duration == nil {
+		return "pause (indefinite)"
+	}
+	desc := "pause " + pause.Duration.String()
+	if pause.Duration.Type == intstr.Int {
+		desc += "s"
+	}
+	if !current {
+		return desc
+	}
+	stepPause, ok := v1alpha1.GetCurrentStepPause(rollout, now)
+	if !ok || stepPause.Deadline == nil {
+		return desc
+	}
+	if stepPause.Remaining == 0 {
+		return fmt.Sprintf("%s (ended at %s)", desc, stepPause.Deadline.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s (%s left, until %s)", desc, duration.HumanDuration(stepPause.Remaining), stepPause.Deadline.UTC().Format(time.RFC3339))
+}
+
+func renderRevisions(b *strings.Builder, rollout *v1alpha1.Rollout, rss []*appsv1.ReplicaSet, pods []*corev1.Pod, now time.Time) {
+	if len(rss) == 0 {
+		return
+	}
+	sorted := make([]*appsv1.ReplicaSet, len(rss))
+	copy(sorted, rss)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		revI, _ := v1alpha1.GetRevision(sorted[i])
+		revJ, _ := v1alpha1.GetRevision(sorted[j])
+		if revI != revJ {
+			return revI > revJ
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	b.WriteString("Revisions:\n")
+	for i, rs := range sorted {
+		prefix, childIndent := branch, indent
+		if i == len(sorted)-1 {
+			prefix, childIndent = lastBranch, lastIndent
+		}
+		revision := "?"
+		if rev, err := v1alpha1.GetRevision(rs); err == nil && rev > 0 {
+			revision = fmt.Sprint(rev)
+		}
+		desired := int32(0)
+		if rs.Spec.Replicas != nil {
+			desired = *rs.Spec.Replicas
+		}
+		fmt.Fprintf(b, "%srevision:%s  %s%s  pods ready:%d/%d\n", prefix, revision, rs.Name,
+			replicaSetRoles(rollout, rs), rs.Status.ReadyReplicas, desired)
+
+		rsPods := podsOf(rs, pods)
+		for j, pod := range rsPods {
+			podPrefix := branch
+			if j == len(rsPods)-1 {
+				podPrefix = lastBranch
+			}
+			fmt.Fprintf(b, "%s%s%s  %s\n", childIndent, podPrefix, pod.Name, describePod(pod, now))
+		}
+	}
+}
+
+func replicaSetRoles(rollout *v1alpha1.Rollout, rs *appsv1.ReplicaSet) string {
+	hash := rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	if hash == "" {
+		return ""
+	}
+	var roles []string
+	if hash == rollout.Status.StableRS {
+		roles = append(roles, "stable")
+	}
+	if hash == rollout.Status.CurrentPodHash && hash != rollout.Status.StableRS {
+		roles = append(roles, "canary")
+	}
+	if len(roles) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(roles, ",") + ")"
+}
+
+func podsOf(rs *appsv1.ReplicaSet, pods []*corev1.Pod) []*corev1.Pod {
+	hash := rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	var rsPods []*corev1.Pod
+	for _, pod := range pods {
+		owned := false
+		for _, ref := range pod.OwnerReferences {
+			if ref.Kind == "ReplicaSet" && ref.Name == rs.Name {
+				owned = true
+			}
+		}
+		if owned || (hash != "" && pod.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] == hash) {
+			rsPods = append(rsPods, pod)
+		}
+	}
+	sort.Slice(rsPods, func(i, j int) bool {
+		return rsPods[i].Name < rsPods[j].Name
+	})
+	return rsPods
+}
+
+func describePod(pod *corev1.Pod, now time.Time) string {
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason
+	}
+	ready, restarts := 0, int32(0)
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+		restarts += cs.RestartCount
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			status = cs.State.Waiting.Reason
+		} else if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			status = cs.State.Terminated.Reason
+		}
+	}
+	if pod.DeletionTimestamp != nil {
+		status = "Terminating"
+	}
+	age := "<unknown>"
+	if !pod.CreationTimestamp.IsZero() {
+		age = duration.HumanDuration(now.Sub(pod.CreationTimestamp.Time))
+	}
+	return fmt.Sprintf("%s  ready:%d/%d  restarts:%d  age:%s", status, ready, len(pod.Spec.Containers), restarts, age)
+}
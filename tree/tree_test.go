@@ -0,0 +1,162 @@
+package tree
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+var now = time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func newRollout() *v1alpha1.Rollout {
+	duration := intstr.FromInt(300)
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"},
+		Spec: v1alpha1.RolloutSpec{
+			Replicas: int32Ptr(4),
+			Strategy: v1alpha1.RolloutStrategy{
+				Canary: &v1alpha1.CanaryStrategy{
+					Steps: []v1alpha1.CanaryStep{
+						{SetWeight: int32Ptr(25)},
+						{Pause: &v1alpha1.RolloutPause{Duration: &duration}},
+						{SetWeight: int32Ptr(50)},
+						{Pause: &v1alpha1.RolloutPause{}},
+					},
+				},
+			},
+		},
+		Status: v1alpha1.RolloutStatus{
+			Replicas:          5,
+			UpdatedReplicas:   1,
+			ReadyReplicas:     5,
+			AvailableReplicas: 5,
+			CurrentPodHash:    "6cf78c66c5",
+			StableRS:          "7bf8f89f7",
+		},
+	}
+}
+
+func newReplicaSet(name, hash string, revision string, replicas, ready int32) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Labels:      map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: hash},
+			Annotations: map[string]string{v1alpha1.RevisionAnnotation: revision},
+		},
+		Spec:   appsv1.ReplicaSetSpec{Replicas: int32Ptr(replicas)},
+		Status: appsv1.ReplicaSetStatus{Replicas: replicas, ReadyReplicas: ready},
+	}
+}
+
+func newPod(name, hash string, age time.Duration, ready bool, restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Labels:            map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: hash},
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: ready, RestartCount: restarts}},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	stableRS := newReplicaSet("demo-7bf8f89f7", "7bf8f89f7", "1", 4, 4)
+	canaryRS := newReplicaSet("demo-6cf78c66c5", "6cf78c66c5", "2", 1, 1)
+	pods := []*corev1.Pod{
+		newPod("demo-7bf8f89f7-b2x4k", "7bf8f89f7", 48*time.Hour, true, 0),
+		newPod("demo-7bf8f89f7-a9d2c", "7bf8f89f7", 48*time.Hour, true, 2),
+		newPod("demo-7bf8f89f7-q8w7e", "7bf8f89f7", 47*time.Hour, true, 0),
+		newPod("demo-7bf8f89f7-z1m3n", "7bf8f89f7", 47*time.Hour, true, 0),
+		newPod("demo-6cf78c66c5-h5j6k", "6cf78c66c5", 2*time.Minute, true, 0),
+	}
+
+	paused := newRollout()
+	paused.Status.CurrentStepIndex = int32Ptr(1)
+	paused.Status.Phase = v1alpha1.RolloutPhasePaused
+	paused.Status.Message = "CanaryPauseStep"
+	paused.Status.PauseConditions = []v1alpha1.PauseCondition{{
+		Reason:    v1alpha1.PauseReasonCanaryPauseStep,
+		StartTime: metav1.NewTime(now.Add(-2 * time.Minute)),
+	}}
+
+	indefinite := newRollout()
+	indefinite.Status.CurrentStepIndex = int32Ptr(3)
+	indefinite.Status.Phase = v1alpha1.RolloutPhasePaused
+	indefinite.Status.Message = "CanaryPauseStep"
+
+	crashing := newPod("demo-6cf78c66c5-h5j6k", "6cf78c66c5", 2*time.Minute, false, 4)
+	crashing.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+	aborted := newRollout()
+	aborted.Status.CurrentStepIndex = int32Ptr(0)
+	v1alpha1.Abort(aborted)
+
+	healthy := newRollout()
+	healthy.Status.CurrentStepIndex = int32Ptr(4)
+	healthy.Status.Phase = v1alpha1.RolloutPhaseHealthy
+	healthy.Status.CurrentPodHash = "6cf78c66c5"
+	healthy.Status.StableRS = "6cf78c66c5"
+	healthy.Status.Replicas = 4
+	healthy.Status.UpdatedReplicas = 4
+	healthy.Status.ReadyReplicas = 4
+	healthy.Status.AvailableReplicas = 4
+	oldRS := newReplicaSet("demo-7bf8f89f7", "7bf8f89f7", "1", 0, 0)
+	newRS := newReplicaSet("demo-6cf78c66c5", "6cf78c66c5", "2", 4, 4)
+	var healthyPods []*corev1.Pod
+	for _, name := range []string{"demo-6cf78c66c5-h5j6k", "demo-6cf78c66c5-c3v4b", "demo-6cf78c66c5-n7m8p", "demo-6cf78c66c5-r2t5y"} {
+		healthyPods = append(healthyPods, newPod(name, "6cf78c66c5", 10*time.Minute, true, 0))
+	}
+
+	tests := []struct {
+		name    string
+		rollout *v1alpha1.Rollout
+		rss     []*appsv1.ReplicaSet
+		pods    []*corev1.Pod
+	}{
+		{name: "paused-timed", rollout: paused, rss: []*appsv1.ReplicaSet{stableRS, canaryRS}, pods: pods},
+		{name: "paused-indefinite", rollout: indefinite, rss: []*appsv1.ReplicaSet{stableRS, canaryRS}, pods: pods},
+		{name: "aborted", rollout: aborted, rss: []*appsv1.ReplicaSet{stableRS, canaryRS}, pods: append(pods[:4:4], crashing)},
+		{name: "healthy", rollout: healthy, rss: []*appsv1.ReplicaSet{oldRS, newRS}, pods: healthyPods},
+		{name: "no-replicasets", rollout: newRollout()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.rollout, tt.rss, tt.pods, now); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("reading golden file: %v (run with -update to create it)", err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("Render() output does not match %s\ngot:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Package upstream converts Rollouts between this package and the upstream argo-rollouts
+// argoproj.io/v1alpha1 Rollout, handled as unstructured objects to avoid depending on argo-rollouts.
+package upstream
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+const (
+	// APIVersion is the apiVersion of the upstream Rollout
+	APIVersion = "argoproj.io/v1alpha1"
+	// Kind is the kind of the upstream Rollout
+	Kind = "Rollout"
+)
+
+// ToUpstream converts a Rollout into an upstream Rollout. The fields of v1alpha1.Rollout exist upstream, only
+// status.observedGeneration changes its representation: the upstream controller stores it as a string. The
+// returned report lists what the upstream controller does not handle like this module: see toUpstreamReport.
+func ToUpstream(rollout *v1alpha1.Rollout) (*unstructured.Unstructured, *v1alpha1.ConversionReport, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(rollout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to convert rollout %s/%s: %w", rollout.Namespace, rollout.Name, err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetAPIVersion(APIVersion)
+	u.SetKind(Kind)
+	if rollout.Status.ObservedGeneration != 0 {
+		err = unstructured.SetNestedField(u.Object, strconv.FormatInt(rollout.Status.ObservedGeneration, 10), "status", "observedGeneration")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return u, toUpstreamReport(rollout), nil
+}
+
+// toUpstreamReport reports the values which are kept by ToUpstream but whose handling is specific to this module:
+// the controller instance label, the ephemeral metadata recorded on the ReplicaSets with the
+// DefaultEphemeralMetadataAnnotationKey annotation, and a pending restart.
+func toUpstreamReport(rollout *v1alpha1.Rollout) *v1alpha1.ConversionReport {
+	report := &v1alpha1.ConversionReport{}
+	if instanceID, ok := rollout.Labels[v1alpha1.LabelKeyControllerInstanceID]; ok {
+		report.Add(fmt.Sprintf("metadata.labels[%s]", v1alpha1.LabelKeyControllerInstanceID),
+			"the rollout is only managed by an upstream controller started with --instance-id %q", instanceID)
+	}
+	if canary := rollout.Spec.Strategy.Canary; canary != nil {
+		if canary.CanaryMetadata != nil {
+			report.Add("spec.strategy.canary.canaryMetadata", "the upstream controller ignores the %s annotation of the "+
+				"ReplicaSets, the metadata applied by this module is not removed by it", v1alpha1.DefaultEphemeralMetadataAnnotationKey)
+		}
+		if canary.StableMetadata != nil {
+			report.Add("spec.strategy.canary.stableMetadata", "the upstream controller ignores the %s annotation of the "+
+				"ReplicaSets, the metadata applied by this module is not removed by it", v1alpha1.DefaultEphemeralMetadataAnnotationKey)
+		}
+	}
+	if v1alpha1.RestartPending(rollout) {
+		report.Add("spec.restartAt", "the restart requested at %s is pending, the upstream controller restarts the pods "+
+			"with its own policy instead of PodsToRestart", rollout.Spec.RestartAt.UTC().Format(time.RFC3339))
+	}
+	return report
+}
+
+// FromUpstream converts an upstream Rollout into a Rollout. The fields which only exist upstream, like the
+// blueGreen strategy, traffic routing or experiment steps, are dropped and listed in the returned report.
+func FromUpstream(u *unstructured.Unstructured) (*v1alpha1.Rollout, *v1alpha1.ConversionReport, error) {
+	if u.GetAPIVersion() != APIVersion || u.GetKind() != Kind {
+		return nil, nil, fmt.Errorf("unexpected object %s %s, expected %s %s", u.GetAPIVersion(), u.GetKind(), APIVersion, Kind)
+	}
+	report := &v1alpha1.ConversionReport{}
+	obj := runtime.DeepCopyJSON(u.Object)
+
+	if observed, found, _ := unstructured.NestedFieldNoCopy(obj, "status", "observedGeneration"); found {
+		if s, ok := observed.(string); ok {
+			generation, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				report.Add("status.observedGeneration", "%q is not a generation number", s)
+				unstructured.RemoveNestedField(obj, "status", "observedGeneration")
+			} else {
+				_ = unstructured.SetNestedField(obj, generation, "status", "observedGeneration")
+			}
+		}
+	}
+
+	rollout := &v1alpha1.Rollout{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, rollout); err != nil {
+		return nil, nil, fmt.Errorf("unable to convert rollout %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+	}
+	rollout.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	rollout.Kind = v1alpha1.RolloutKind
+
+	converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(rollout)
+	if err != nil {
+		return nil, nil, err
+	}
+	reportDropped(report, "", obj, converted)
+	return rollout, report, nil
+}
+
+// reportDropped adds the non empty values of the source which are missing in the converted object.
+func reportDropped(report *v1alpha1.ConversionReport, path string, source, converted interface{}) {
+	switch s := source.(type) {
+	case map[string]interface{}:
+		c, _ := converted.(map[string]interface{})
+		keys := make([]string, 0, len(s))
+		for key := range s {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			value, ok := c[key]
+			if !ok {
+				if !isEmpty(s[key]) {
+					report.Add(fieldPath, "field does not exist in %s", v1alpha1.SchemeGroupVersion)
+				}
+				continue
+			}
+			reportDropped(report, fieldPath, s[key], value)
+		}
+	case []interface{}:
+		c, _ := converted.([]interface{})
+		for i := range s {
+			if i >= len(c) {
+				report.Add(fmt.Sprintf("%s[%d]", path, i), "item is dropped")
+				continue
+			}
+			reportDropped(report, fmt.Sprintf("%s[%d]", path, i), s[i], c[i])
+		}
+	}
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case int64:
+		return v == 0
+	case float64:
+		return v == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package upstream
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+func TestRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("testdata/upstream-rollout.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+
+	rollout, report, err := FromUpstream(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rollout.Status.ObservedGeneration != 3 || *rollout.Spec.Replicas != 4 || len(rollout.Spec.Strategy.Canary.Steps) != 4 {
+		t.Errorf("unexpected rollout %v", rollout)
+	}
+	expected := []string{
+		"spec.strategy.canary.canaryService",
+		"spec.strategy.canary.stableService",
+		"spec.strategy.canary.steps[2].experiment",
+		"spec.strategy.canary.trafficRouting",
+		"status.workloadObservedGeneration",
+	}
+	if len(report.Lost) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, report.Lost)
+	}
+	for i, path := range expected {
+		if report.Lost[i].Path != path {
+			t.Errorf("expected %s, got %s", path, report.Lost[i].Path)
+		}
+	}
+
+	exported, report, err := ToUpstream(rollout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Empty() {
+		t.Errorf("expected nothing to report converting back, got %v", report.Lost)
+	}
+	if exported.GetAPIVersion() != APIVersion || exported.GetKind() != Kind {
+		t.Errorf("unexpected type %s %s", exported.GetAPIVersion(), exported.GetKind())
+	}
+	observed, _, _ := unstructured.NestedString(exported.Object, "status", "observedGeneration")
+	if observed != "3" {
+		t.Errorf("expected observedGeneration \"3\", got %q", observed)
+	}
+	maxSurge, _, _ := unstructured.NestedString(exported.Object, "spec", "strategy", "canary", "maxSurge")
+	if maxSurge != "25%" {
+		t.Errorf("expected maxSurge 25%%, got %q", maxSurge)
+	}
+}
+
+func TestToUpstreamReport(t *testing.T) {
+	restartAt := metav1.NewTime(time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC))
+	rollout := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default",
+			Labels: map[string]string{v1alpha1.LabelKeyControllerInstanceID: "blue"}},
+		Spec: v1alpha1.RolloutSpec{
+			RestartAt: &restartAt,
+			Strategy: v1alpha1.RolloutStrategy{Canary: &v1alpha1.CanaryStrategy{
+				CanaryMetadata: &v1alpha1.PodTemplateMetadata{Labels: map[string]string{"role": "canary"}},
+			}},
+		},
+	}
+	exported, report, err := ToUpstream(rollout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"metadata.labels[argo-rollouts.argoproj.io/controller-instance-id]",
+		"spec.strategy.canary.canaryMetadata",
+		"spec.restartAt",
+	}
+	if len(report.Lost) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, report.Lost)
+	}
+	for i, path := range expected {
+		if report.Lost[i].Path != path {
+			t.Errorf("expected %s, got %s", path, report.Lost[i].Path)
+		}
+	}
+	if restart, _, _ := unstructured.NestedString(exported.Object, "spec", "restartAt"); restart != "2022-11-01T10:00:00Z" {
+		t.Errorf("expected the reported fields to be kept, got restartAt %q", restart)
+	}
+
+	rollout.Status.RestartedAt = &restartAt
+	if _, report, _ := ToUpstream(rollout); len(report.Lost) != 2 {
+		t.Errorf("expected a completed restart not to be reported, got %v", report.Lost)
+	}
+}
@@ -0,0 +1,59 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RolloutAbortedReason indicates that the rollout was aborted, it is set on the Progressing condition and
+	// prefixes Status.Message of the Degraded phase.
+	RolloutAbortedReason = "RolloutAborted"
+	// RolloutAbortedMessage is the message of the Progressing condition when the rollout was aborted
+	RolloutAbortedMessage = "Rollout aborted update to the canary pod template, scaling back to the stable ReplicaSet"
+	// RolloutRetriedReason indicates that an aborted rollout was retried
+	RolloutRetriedReason = "RolloutRetried"
+	// RolloutRetriedMessage is the message of the Progressing condition when an aborted rollout was retried
+	RolloutRetriedMessage = "Rollout was retried after being aborted"
+)
+
+// IsAborted returns true if the rollout was aborted and has not been retried since.
+func IsAborted(rollout *Rollout) bool {
+	return rollout.Status.Abort
+}
+
+// Abort marks the rollout status as aborted: the controller scales the canary down and moves all traffic
+// back to the stable ReplicaSet. The phase becomes Degraded until the rollout is retried or the spec changes.
+// The caller persists the change with RolloutInterface.UpdateStatus.
+func Abort(rollout *Rollout) {
+	if rollout.Status.Abort {
+		return
+	}
+	now := metav1.Now()
+	rollout.Status.Abort = true
+	rollout.Status.AbortedAt = &now
+	rollout.Status.Phase = RolloutPhaseDegraded
+	rollout.Status.Message = fmt.Sprintf("%s: %s", RolloutAbortedReason, RolloutAbortedMessage)
+	cond := NewRolloutCondition(RolloutProgressing, corev1.ConditionFalse, RolloutAbortedReason, RolloutAbortedMessage)
+	SetRolloutCondition(&rollout.Status, *cond)
+}
+
+// Retry clears the abort of a rollout so the update to the canary pod template starts again from the first
+// step. The caller persists the change with RolloutInterface.UpdateStatus.
+func Retry(rollout *Rollout) {
+	if !rollout.Status.Abort {
+		return
+	}
+	rollout.Status.Abort = false
+	rollout.Status.AbortedAt = nil
+	if rollout.Spec.Strategy.Canary != nil && len(rollout.Spec.Strategy.Canary.Steps) > 0 {
+		zero := int32(0)
+		rollout.Status.CurrentStepIndex = &zero
+	}
+	rollout.Status.Phase = RolloutPhaseProgressing
+	rollout.Status.Message = ""
+	cond := NewRolloutCondition(RolloutProgressing, corev1.ConditionTrue, RolloutRetriedReason, RolloutRetriedMessage)
+	SetRolloutCondition(&rollout.Status, *cond)
+}
@@ -0,0 +1,44 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestAbortRetry(t *testing.T) {
+	index := int32(2)
+	ro := &Rollout{
+		Spec: RolloutSpec{Strategy: RolloutStrategy{Canary: &CanaryStrategy{
+			Steps: []CanaryStep{weightStep(10), weightStep(50), {Pause: &RolloutPause{}}},
+		}}},
+		Status: RolloutStatus{CurrentStepIndex: &index, Phase: RolloutPhasePaused},
+	}
+
+	Abort(ro)
+	if !IsAborted(ro) || ro.Status.AbortedAt == nil {
+		t.Fatalf("rollout should be aborted")
+	}
+	if ro.Status.Phase != RolloutPhaseDegraded || ro.Status.Message == "" {
+		t.Errorf("expected Degraded phase with a message, got %s %q", ro.Status.Phase, ro.Status.Message)
+	}
+	cond := GetRolloutCondition(ro.Status, RolloutProgressing)
+	if cond == nil || cond.Status != corev1.ConditionFalse || cond.Reason != RolloutAbortedReason {
+		t.Errorf("unexpected progressing condition %v", cond)
+	}
+
+	Retry(ro)
+	if IsAborted(ro) || ro.Status.AbortedAt != nil {
+		t.Fatalf("rollout should not be aborted")
+	}
+	if *ro.Status.CurrentStepIndex != 0 {
+		t.Errorf("expected the rollout to restart at step 0, got %d", *ro.Status.CurrentStepIndex)
+	}
+	cond = GetRolloutCondition(ro.Status, RolloutProgressing)
+	if cond == nil || cond.Status != corev1.ConditionTrue || cond.Reason != RolloutRetriedReason {
+		t.Errorf("unexpected progressing condition %v", cond)
+	}
+	if len(ro.Status.Conditions) != 1 {
+		t.Errorf("expected a single condition, got %d", len(ro.Status.Conditions))
+	}
+}
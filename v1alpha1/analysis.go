@@ -0,0 +1,111 @@
+package v1alpha1
+
+import (
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultSuccessfulRunHistoryLimit is the number of successful analysis runs and experiments retained
+	// when SuccessfulRunHistoryLimit is not set
+	DefaultSuccessfulRunHistoryLimit = int32(5)
+	// DefaultUnsuccessfulRunHistoryLimit is the number of unsuccessful analysis runs and experiments retained
+	// when UnsuccessfulRunHistoryLimit is not set
+	DefaultUnsuccessfulRunHistoryLimit = int32(5)
+)
+
+// AnalysisPhase is the overall phase of an AnalysisRun or an Experiment
+type AnalysisPhase string
+
+// Possible AnalysisPhase values
+const (
+	AnalysisPhasePending      AnalysisPhase = "Pending"
+	AnalysisPhaseRunning      AnalysisPhase = "Running"
+	AnalysisPhaseSuccessful   AnalysisPhase = "Successful"
+	AnalysisPhaseFailed       AnalysisPhase = "Failed"
+	AnalysisPhaseError        AnalysisPhase = "Error"
+	AnalysisPhaseInconclusive AnalysisPhase = "Inconclusive"
+)
+
+// Completed returns whether or not the analysis status is considered completed
+func (as AnalysisPhase) Completed() bool {
+	switch as {
+	case AnalysisPhaseSuccessful, AnalysisPhaseFailed, AnalysisPhaseError, AnalysisPhaseInconclusive:
+		return true
+	}
+	return false
+}
+
+// Unsuccessful returns whether the analysis completed with the "Error", "Failed" or "Inconclusive" phase
+func (as AnalysisPhase) Unsuccessful() bool {
+	return as.Completed() && as != AnalysisPhaseSuccessful
+}
+
+// RunHistoryItem is an AnalysisRun or an Experiment considered for garbage collection
+type RunHistoryItem struct {
+	Name              string
+	Phase             AnalysisPhase
+	CreationTimestamp metav1.Time
+}
+
+// GetSuccessfulRunHistoryLimit returns the number of successful analysis runs and experiments to retain.
+func GetSuccessfulRunHistoryLimit(rollout *Rollout) int32 {
+	if rollout.Spec.Analysis != nil && rollout.Spec.Analysis.SuccessfulRunHistoryLimit != nil {
+		return *rollout.Spec.Analysis.SuccessfulRunHistoryLimit
+	}
+	return DefaultSuccessfulRunHistoryLimit
+}
+
+// GetUnsuccessfulRunHistoryLimit returns the number of unsuccessful analysis runs and experiments to retain.
+func GetUnsuccessfulRunHistoryLimit(rollout *Rollout) int32 {
+	if rollout.Spec.Analysis != nil && rollout.Spec.Analysis.UnsuccessfulRunHistoryLimit != nil {
+		return *rollout.Spec.Analysis.UnsuccessfulRunHistoryLimit
+	}
+	return DefaultUnsuccessfulRunHistoryLimit
+}
+
+// GetRunsToDelete returns the completed analysis runs or experiments exceeding the history limits of the
+// rollout, oldest first. The newest items of each kind of outcome are retained, items which are still
+// pending or running are never deleted.
+func GetRunsToDelete(rollout *Rollout, items []RunHistoryItem) []RunHistoryItem {
+	sorted := make([]RunHistoryItem, len(items))
+	copy(sorted, items)
+	// newest first
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreationTimestamp.Equal(&sorted[j].CreationTimestamp) {
+			return sorted[j].CreationTimestamp.Before(&sorted[i].CreationTimestamp)
+		}
+		return sorted[i].Name > sorted[j].Name
+	})
+
+	successfulLimit := GetSuccessfulRunHistoryLimit(rollout)
+	unsuccessfulLimit := GetUnsuccessfulRunHistoryLimit(rollout)
+	var successful, unsuccessful int32
+	var toDelete []RunHistoryItem
+	for _, item := range sorted {
+		switch {
+		case item.Phase == AnalysisPhaseSuccessful:
+			successful++
+			if successful > successfulLimit {
+				toDelete = append(toDelete, item)
+			}
+		case item.Phase.Unsuccessful():
+			unsuccessful++
+			if unsuccessful > unsuccessfulLimit {
+				toDelete = append(toDelete, item)
+			}
+		}
+	}
+	// oldest first
+	for i, j := 0, len(toDelete)-1; i < j; i, j = i+1, j-1 {
+		toDelete[i], toDelete[j] = toDelete[j], toDelete[i]
+	}
+	return toDelete
+}
+
+// GetAnalysisRunsAndExperimentsToDelete applies GetRunsToDelete to the analysis runs and the experiments
+// of a rollout, the history limits apply to each of them separately.
+func GetAnalysisRunsAndExperimentsToDelete(rollout *Rollout, runs, experiments []RunHistoryItem) ([]RunHistoryItem, []RunHistoryItem) {
+	return GetRunsToDelete(rollout, runs), GetRunsToDelete(rollout, experiments)
+}
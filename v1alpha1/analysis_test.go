@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetRunsToDelete(t *testing.T) {
+	now := time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+	item := func(name string, phase AnalysisPhase, age time.Duration) RunHistoryItem {
+		return RunHistoryItem{Name: name, Phase: phase, CreationTimestamp: metav1.NewTime(now.Add(-age))}
+	}
+	one := int32(1)
+	ro := &Rollout{Spec: RolloutSpec{Analysis: &AnalysisRunStrategy{SuccessfulRunHistoryLimit: &one}}}
+	runs := []RunHistoryItem{
+		item("succeeded-new", AnalysisPhaseSuccessful, time.Minute),
+		item("succeeded-old", AnalysisPhaseSuccessful, 2*time.Hour),
+		item("succeeded-older", AnalysisPhaseSuccessful, 3*time.Hour),
+		item("running", AnalysisPhaseRunning, 4*time.Hour),
+		item("failed", AnalysisPhaseFailed, time.Hour),
+		item("error", AnalysisPhaseError, time.Hour),
+		item("inconclusive", AnalysisPhaseInconclusive, time.Hour),
+	}
+
+	toDelete := GetRunsToDelete(ro, runs)
+	if len(toDelete) != 2 || toDelete[0].Name != "succeeded-older" || toDelete[1].Name != "succeeded-old" {
+		t.Errorf("unexpected runs to delete %v", toDelete)
+	}
+
+	zero := int32(0)
+	ro.Spec.Analysis.UnsuccessfulRunHistoryLimit = &zero
+	runsToDelete, experimentsToDelete := GetAnalysisRunsAndExperimentsToDelete(ro, runs, runs[:2])
+	if len(runsToDelete) != 5 {
+		t.Errorf("expected 5 runs to delete, got %v", runsToDelete)
+	}
+	if len(experimentsToDelete) != 1 || experimentsToDelete[0].Name != "succeeded-old" {
+		t.Errorf("unexpected experiments to delete %v", experimentsToDelete)
+	}
+}
+
+func TestSetRolloutDefaults(t *testing.T) {
+	ro := &Rollout{}
+	SetRolloutDefaults(ro)
+	if *ro.Spec.Replicas != DefaultReplicas || *ro.Spec.RevisionHistoryLimit != DefaultRevisionHistoryLimit {
+		t.Errorf("unexpected defaults %v", ro.Spec)
+	}
+	if GetSuccessfulRunHistoryLimit(ro) != DefaultSuccessfulRunHistoryLimit ||
+		GetUnsuccessfulRunHistoryLimit(ro) != DefaultUnsuccessfulRunHistoryLimit {
+		t.Errorf("unexpected analysis defaults %v", ro.Spec.Analysis)
+	}
+}
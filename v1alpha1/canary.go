@@ -40,3 +40,38 @@ type SetCanaryScale struct {
 }
 
 type CanaryStatus struct{}
+
+// GetCurrentCanaryStep returns the current canary step and its index. If the rollout has no canary strategy,
+// or the steps are all completed, a nil step is returned.
+func GetCurrentCanaryStep(rollout *Rollout) (*CanaryStep, *int32) {
+	if rollout.Spec.Strategy.Canary == nil || len(rollout.Spec.Strategy.Canary.Steps) == 0 {
+		return nil, nil
+	}
+	currentStepIndex := int32(0)
+	if rollout.Status.CurrentStepIndex != nil {
+		currentStepIndex = *rollout.Status.CurrentStepIndex
+	}
+	if currentStepIndex < 0 || int(currentStepIndex) >= len(rollout.Spec.Strategy.Canary.Steps) {
+		return nil, &currentStepIndex
+	}
+	return &rollout.Spec.Strategy.Canary.Steps[currentStepIndex], &currentStepIndex
+}
+
+// GetCurrentSetWeight returns the weight of the canary set by the last SetWeight step up to the current step.
+// An aborted rollout has a weight of 0, and a rollout which went through all its steps a weight of 100.
+func GetCurrentSetWeight(rollout *Rollout) int32 {
+	if rollout.Status.Abort {
+		return 0
+	}
+	currentStep, currentStepIndex := GetCurrentCanaryStep(rollout)
+	if currentStep == nil {
+		return 100
+	}
+	for i := *currentStepIndex; i >= 0; i-- {
+		step := rollout.Spec.Strategy.Canary.Steps[i]
+		if step.SetWeight != nil {
+			return *step.SetWeight
+		}
+	}
+	return 0
+}
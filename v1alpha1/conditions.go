@@ -0,0 +1,64 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewRolloutCondition creates a new rollout condition.
+func NewRolloutCondition(condType RolloutConditionType, status corev1.ConditionStatus, reason, message string) *RolloutCondition {
+	return &RolloutCondition{
+		Type:               condType,
+		Status:             status,
+		LastUpdateTime:     metav1.Now(),
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// GetRolloutCondition returns the condition with the provided type.
+func GetRolloutCondition(status RolloutStatus, condType RolloutConditionType) *RolloutCondition {
+	for i := range status.Conditions {
+		c := status.Conditions[i]
+		if c.Type == condType {
+			return &c
+		}
+	}
+	return nil
+}
+
+// SetRolloutCondition updates the rollout to include the provided condition. If the condition that
+// we are about to add already exists and has the same status and reason, then we are not going to update
+// by returning false. Returns true if the condition was updated
+func SetRolloutCondition(status *RolloutStatus, condition RolloutCondition) bool {
+	currentCond := GetRolloutCondition(*status, condition.Type)
+	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason &&
+		currentCond.Message == condition.Message {
+		return false
+	}
+	// Do not update lastTransitionTime if the status of the condition doesn't change.
+	if currentCond != nil && currentCond.Status == condition.Status {
+		condition.LastTransitionTime = currentCond.LastTransitionTime
+	}
+	newConditions := filterOutCondition(status.Conditions, condition.Type)
+	status.Conditions = append(newConditions, condition)
+	return true
+}
+
+// RemoveRolloutCondition removes the rollout condition with the provided type.
+func RemoveRolloutCondition(status *RolloutStatus, condType RolloutConditionType) {
+	status.Conditions = filterOutCondition(status.Conditions, condType)
+}
+
+// filterOutCondition returns a new slice of rollout conditions without conditions with the provided type.
+func filterOutCondition(conditions []RolloutCondition, condType RolloutConditionType) []RolloutCondition {
+	var newConditions []RolloutCondition
+	for _, c := range conditions {
+		if c.Type == condType {
+			continue
+		}
+		newConditions = append(newConditions, c)
+	}
+	return newConditions
+}
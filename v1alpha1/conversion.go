@@ -0,0 +1,4 @@
+package v1alpha1
+
+// Hub marks v1alpha1 as the version the other API versions of the Rollout convert to and from.
+func (*Rollout) Hub() {}
@@ -6,6 +6,32 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisRunStrategy) DeepCopyInto(out *AnalysisRunStrategy) {
+	*out = *in
+	if in.SuccessfulRunHistoryLimit != nil {
+		in, out := &in.SuccessfulRunHistoryLimit, &out.SuccessfulRunHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UnsuccessfulRunHistoryLimit != nil {
+		in, out := &in.UnsuccessfulRunHistoryLimit, &out.UnsuccessfulRunHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisRunStrategy.
+func (in *AnalysisRunStrategy) DeepCopy() *AnalysisRunStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisRunStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AntiAffinity) DeepCopyInto(out *AntiAffinity) {
 	*out = *in
@@ -100,6 +126,16 @@ func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.CanaryMetadata != nil {
+		in, out := &in.CanaryMetadata, &out.CanaryMetadata
+		*out = new(PodTemplateMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StableMetadata != nil {
+		in, out := &in.StableMetadata, &out.StableMetadata
+		*out = new(PodTemplateMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -354,6 +390,11 @@ func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.Template.DeepCopyInto(&out.Template)
+	if in.WorkloadRef != nil {
+		in, out := &in.WorkloadRef, &out.WorkloadRef
+		*out = new(ObjectRef)
+		**out = **in
+	}
 
 	in.Strategy.DeepCopyInto(&out.Strategy)
 	if in.RevisionHistoryLimit != nil {
@@ -361,6 +402,20 @@ func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RestartAt != nil {
+		in, out := &in.RestartAt, &out.RestartAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(AnalysisRunStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 
 	return
 }
@@ -385,7 +440,10 @@ func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-
+	if in.AbortedAt != nil {
+		in, out := &in.AbortedAt, &out.AbortedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.CurrentStepIndex != nil {
 		in, out := &in.CurrentStepIndex, &out.CurrentStepIndex
 		*out = new(int32)
@@ -404,6 +462,10 @@ func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
 		}
 	}
 	in.Canary.DeepCopyInto(&out.Canary)
+	if in.RestartedAt != nil {
+		in, out := &in.RestartedAt, &out.RestartedAt
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
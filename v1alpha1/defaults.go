@@ -0,0 +1,37 @@
+package v1alpha1
+
+const (
+	// DefaultReplicas is the number of desired pods when Replicas is not set
+	DefaultReplicas = int32(1)
+	// DefaultProgressDeadlineSeconds is the maximum time in seconds a rollout can take to make progress
+	// when ProgressDeadlineSeconds is not set
+	DefaultProgressDeadlineSeconds = int32(600)
+)
+
+// SetRolloutDefaults sets the default values of the fields which are not set.
+func SetRolloutDefaults(rollout *Rollout) {
+	spec := &rollout.Spec
+	if spec.Replicas == nil {
+		replicas := DefaultReplicas
+		spec.Replicas = &replicas
+	}
+	if spec.RevisionHistoryLimit == nil {
+		limit := DefaultRevisionHistoryLimit
+		spec.RevisionHistoryLimit = &limit
+	}
+	if spec.ProgressDeadlineSeconds == nil {
+		deadline := DefaultProgressDeadlineSeconds
+		spec.ProgressDeadlineSeconds = &deadline
+	}
+	if spec.Analysis == nil {
+		spec.Analysis = &AnalysisRunStrategy{}
+	}
+	if spec.Analysis.SuccessfulRunHistoryLimit == nil {
+		limit := DefaultSuccessfulRunHistoryLimit
+		spec.Analysis.SuccessfulRunHistoryLimit = &limit
+	}
+	if spec.Analysis.UnsuccessfulRunHistoryLimit == nil {
+		limit := DefaultUnsuccessfulRunHistoryLimit
+		spec.Analysis.UnsuccessfulRunHistoryLimit = &limit
+	}
+}
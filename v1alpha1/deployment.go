@@ -0,0 +1,316 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// annotations managed by the deployment controller or kubectl, which are not carried over by the conversions
+var deploymentOnlyAnnotations = []string{
+	"deployment.kubernetes.io/revision",
+	"deployment.kubernetes.io/desired-replicas",
+	"deployment.kubernetes.io/max-replicas",
+	corev1.LastAppliedConfigAnnotation,
+	RevisionAnnotation,
+}
+
+// DeploymentConversionOptions configures NewRolloutFromDeployment.
+type DeploymentConversionOptions struct {
+	// GenerateSteps adds the DefaultCanarySteps plan to the canary strategy. Without steps the rollout
+	// updates the pods like the Deployment did.
+	GenerateSteps bool
+	// UseWorkloadRef references the Deployment through Spec.WorkloadRef instead of copying its pod template.
+	UseWorkloadRef bool
+}
+
+// DefaultCanarySteps returns a default step plan: the canary is promoted to 20% and waits for a manual
+// promotion, then it is promoted to 50% and 80% with a 10 minutes pause at each step.
+func DefaultCanarySteps() []CanaryStep {
+	weight := func(w int32) CanaryStep {
+		return CanaryStep{SetWeight: &w}
+	}
+	return []CanaryStep{
+		weight(20),
+		{Pause: &RolloutPause{}},
+		weight(50),
+		{Pause: &RolloutPause{Duration: DurationFromString("10m")}},
+		weight(80),
+		{Pause: &RolloutPause{Duration: DurationFromString("10m")}},
+	}
+}
+
+// NewRolloutFromDeployment converts a Deployment into a Rollout with a canary strategy. The RollingUpdate
+// maxSurge and maxUnavailable of the Deployment are kept, the Recreate strategy is mapped to a maxSurge of 0
+// and a maxUnavailable of 100%, which is the closest canary behaviour.
+func NewRolloutFromDeployment(deploy *appsv1.Deployment, opts DeploymentConversionOptions) (*Rollout, error) {
+	if deploy.Spec.Selector == nil {
+		return nil, fmt.Errorf("deployment %s/%s has no selector", deploy.Namespace, deploy.Name)
+	}
+
+	rollout := &Rollout{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       RolloutKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        deploy.Name,
+			Namespace:   deploy.Namespace,
+			Labels:      copyStringMap(deploy.Labels),
+			Annotations: copyStringMap(deploy.Annotations),
+		},
+		Spec: RolloutSpec{
+			Replicas:                copyInt32(deploy.Spec.Replicas),
+			Selector:                deploy.Spec.Selector.DeepCopy(),
+			MinReadySeconds:         deploy.Spec.MinReadySeconds,
+			RevisionHistoryLimit:    copyInt32(deploy.Spec.RevisionHistoryLimit),
+			Paused:                  deploy.Spec.Paused,
+			ProgressDeadlineSeconds: copyInt32(deploy.Spec.ProgressDeadlineSeconds),
+		},
+	}
+	for _, key := range deploymentOnlyAnnotations {
+		delete(rollout.Annotations, key)
+	}
+	if len(rollout.Annotations) == 0 {
+		rollout.Annotations = nil
+	}
+
+	if opts.UseWorkloadRef {
+		rollout.Spec.WorkloadRef = &ObjectRef{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+			Name:       deploy.Name,
+		}
+	} else {
+		deploy.Spec.Template.DeepCopyInto(&rollout.Spec.Template)
+	}
+
+	canary := &CanaryStrategy{}
+	switch deploy.Spec.Strategy.Type {
+	case appsv1.RecreateDeploymentStrategyType:
+		maxSurge := intstr.FromInt(0)
+		maxUnavailable := intstr.FromString("100%")
+		canary.MaxSurge = &maxSurge
+		canary.MaxUnavailable = &maxUnavailable
+	default:
+		if rollingUpdate := deploy.Spec.Strategy.RollingUpdate; rollingUpdate != nil {
+			canary.MaxSurge = copyIntOrString(rollingUpdate.MaxSurge)
+			canary.MaxUnavailable = copyIntOrString(rollingUpdate.MaxUnavailable)
+		}
+	}
+	if opts.GenerateSteps {
+		canary.Steps = DefaultCanarySteps()
+	}
+	rollout.Spec.Strategy.Canary = canary
+	return rollout, nil
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func copyInt32(in *int32) *int32 {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+func copyIntOrString(in *intstr.IntOrString) *intstr.IntOrString {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+// DeploymentExportOptions configures NewDeploymentFromRollout.
+type DeploymentExportOptions struct {
+	// UseStableTemplate uses the pod template of StableReplicaSet instead of the rollout template, which may
+	// be an in-flight canary template.
+	UseStableTemplate bool
+	// StableReplicaSet is the stable ReplicaSet of the rollout, required by UseStableTemplate
+	StableReplicaSet *appsv1.ReplicaSet
+}
+
+// FieldLoss is a field which could not be carried over by a conversion, or whose meaning changes.
+type FieldLoss struct {
+	// Path is the path of the field in the source object
+	Path string `json:"path"`
+	// Message explains what was lost
+	Message string `json:"message"`
+}
+
+// ConversionReport lists what was lost or changes meaning in a conversion.
+type ConversionReport struct {
+	Lost []FieldLoss `json:"lost,omitempty"`
+}
+
+// Add records a field which could not be converted.
+func (r *ConversionReport) Add(path, format string, args ...interface{}) {
+	r.Lost = append(r.Lost, FieldLoss{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// Empty returns true if the conversion was lossless.
+func (r *ConversionReport) Empty() bool {
+	return len(r.Lost) == 0
+}
+
+// NewDeploymentFromRollout converts a Rollout into a Deployment with a RollingUpdate strategy using the canary
+// maxSurge and maxUnavailable. The step plan and the other rollout only features are dropped and listed in
+// the returned report. A rollout without selector, which references its workload, gets the selector of the
+// stable ReplicaSet when its template is used.
+func NewDeploymentFromRollout(rollout *Rollout, opts DeploymentExportOptions) (*appsv1.Deployment, *ConversionReport, error) {
+	report := &ConversionReport{}
+	spec := rollout.Spec
+
+	var template *corev1.PodTemplateSpec
+	switch {
+	case opts.UseStableTemplate:
+		if opts.StableReplicaSet == nil {
+			return nil, nil, fmt.Errorf("rollout %s/%s: the stable template was requested without stable ReplicaSet", rollout.Namespace, rollout.Name)
+		}
+		template = stablePodTemplate(rollout, opts.StableReplicaSet)
+	case spec.WorkloadRef != nil && spec.EmptyTemplate() && len(spec.Template.Spec.Containers) == 0:
+		return nil, nil, fmt.Errorf("rollout %s/%s references its template from %s %s, use the stable ReplicaSet template instead",
+			rollout.Namespace, rollout.Name, spec.WorkloadRef.Kind, spec.WorkloadRef.Name)
+	default:
+		template = spec.Template.DeepCopy()
+	}
+
+	selector := spec.Selector.DeepCopy()
+	if selector == nil && opts.UseStableTemplate {
+		selector = stableSelector(opts.StableReplicaSet)
+	}
+	if selector == nil {
+		return nil, nil, fmt.Errorf("rollout %s/%s has no selector", rollout.Namespace, rollout.Name)
+	}
+
+	deploy := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        rollout.Name,
+			Namespace:   rollout.Namespace,
+			Labels:      copyStringMap(rollout.Labels),
+			Annotations: copyStringMap(rollout.Annotations),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas:                copyInt32(spec.Replicas),
+			Selector:                selector,
+			Template:                *template,
+			MinReadySeconds:         spec.MinReadySeconds,
+			RevisionHistoryLimit:    copyInt32(spec.RevisionHistoryLimit),
+			Paused:                  spec.Paused,
+			ProgressDeadlineSeconds: copyInt32(spec.ProgressDeadlineSeconds),
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+			},
+		},
+	}
+	for _, key := range deploymentOnlyAnnotations {
+		delete(deploy.Annotations, key)
+	}
+	if len(deploy.Annotations) == 0 {
+		deploy.Annotations = nil
+	}
+
+	if canary := spec.Strategy.Canary; canary != nil {
+		if canary.MaxSurge != nil || canary.MaxUnavailable != nil {
+			deploy.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{
+				MaxSurge:       copyIntOrString(canary.MaxSurge),
+				MaxUnavailable: copyIntOrString(canary.MaxUnavailable),
+			}
+		}
+		if len(canary.Steps) > 0 {
+			report.Add("spec.strategy.canary.steps", "%d steps are dropped, the pods are updated in a single rolling update", len(canary.Steps))
+		}
+		if canary.CanaryMetadata != nil {
+			report.Add("spec.strategy.canary.canaryMetadata", "canary pods metadata is dropped")
+		}
+		if canary.StableMetadata != nil {
+			report.Add("spec.strategy.canary.stableMetadata", "stable pods metadata is dropped")
+		}
+	}
+	if spec.WorkloadRef != nil {
+		report.Add("spec.workloadRef", "the reference to %s %s is dropped, the template is copied", spec.WorkloadRef.Kind, spec.WorkloadRef.Name)
+	}
+	if spec.RestartAt != nil {
+		report.Add("spec.restartAt", "the restart requested at %s is dropped", spec.RestartAt.UTC().Format(time.RFC3339))
+	}
+	if spec.ProgressDeadlineAbort {
+		report.Add("spec.progressDeadlineAbort", "the update is not aborted when the progress deadline is exceeded")
+	}
+	if spec.Analysis != nil {
+		report.Add("spec.analysis", "the analysis runs retention is dropped")
+	}
+	if rollout.Status.CurrentStepIndex != nil {
+		report.Add("status.currentStepIndex", "the progress through the steps (step %d) is lost", *rollout.Status.CurrentStepIndex)
+	}
+	if rollout.Status.Abort {
+		report.Add("status.abort", "the rollout is aborted, the Deployment is not")
+	}
+	return deploy, report, nil
+}
+
+// stableSelector returns the selector of the stable ReplicaSet without the pod template hash, nil if it has none.
+func stableSelector(stableRS *appsv1.ReplicaSet) *metav1.LabelSelector {
+	if stableRS.Spec.Selector == nil {
+		return nil
+	}
+	selector := stableRS.Spec.Selector.DeepCopy()
+	delete(selector.MatchLabels, DefaultRolloutUniqueLabelKey)
+	var exprs []metav1.LabelSelectorRequirement
+	for _, expr := range selector.MatchExpressions {
+		if expr.Key != DefaultRolloutUniqueLabelKey {
+			exprs = append(exprs, expr)
+		}
+	}
+	selector.MatchExpressions = exprs
+	return selector
+}
+
+// stablePodTemplate returns the pod template of the stable ReplicaSet, without the pod template hash label
+// and the ephemeral metadata recorded for the rollout. The keys the pod template of the rollout also defines
+// are set back to its values.
+func stablePodTemplate(rollout *Rollout, stableRS *appsv1.ReplicaSet) *corev1.PodTemplateSpec {
+	template := stableRS.Spec.Template.DeepCopy()
+	delete(template.Labels, DefaultRolloutUniqueLabelKey)
+	base := rollout.Spec.Template
+	applied := appliedEphemeralMetadata(stableRS)
+	for key := range applied.Labels {
+		if value, ok := base.Labels[key]; ok {
+			if template.Labels == nil {
+				template.Labels = map[string]string{}
+			}
+			template.Labels[key] = value
+		} else {
+			delete(template.Labels, key)
+		}
+	}
+	for key := range applied.Annotations {
+		if value, ok := base.Annotations[key]; ok {
+			if template.Annotations == nil {
+				template.Annotations = map[string]string{}
+			}
+			template.Annotations[key] = value
+		} else {
+			delete(template.Annotations, key)
+		}
+	}
+	return template
+}
@@ -0,0 +1,137 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newTestDeployment() *appsv1.Deployment {
+	replicas := int32(3)
+	limit := int32(5)
+	maxSurge := intstr.FromString("50%")
+	maxUnavailable := intstr.FromInt(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "demo",
+			Namespace:   "default",
+			Labels:      map[string]string{"app": "demo"},
+			Annotations: map[string]string{"deployment.kubernetes.io/revision": "4", "team": "web"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "demo"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "nginx:1.23"}}},
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type:          appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &maxSurge, MaxUnavailable: &maxUnavailable},
+			},
+			MinReadySeconds:      10,
+			RevisionHistoryLimit: &limit,
+			Paused:               true,
+		},
+	}
+}
+
+func TestNewRolloutFromDeployment(t *testing.T) {
+	deploy := newTestDeployment()
+	ro, err := NewRolloutFromDeployment(deploy, DeploymentConversionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *ro.Spec.Replicas != 3 || ro.Spec.MinReadySeconds != 10 || *ro.Spec.RevisionHistoryLimit != 5 || !ro.Spec.Paused {
+		t.Errorf("unexpected spec %v", ro.Spec)
+	}
+	if ro.Spec.Template.Spec.Containers[0].Image != "nginx:1.23" || ro.Spec.WorkloadRef != nil {
+		t.Errorf("expected the template to be copied")
+	}
+	canary := ro.Spec.Strategy.Canary
+	if canary.MaxSurge.String() != "50%" || canary.MaxUnavailable.String() != "1" || len(canary.Steps) != 0 {
+		t.Errorf("unexpected canary strategy %v", canary)
+	}
+	if _, ok := ro.Annotations["deployment.kubernetes.io/revision"]; ok || ro.Annotations["team"] != "web" {
+		t.Errorf("unexpected annotations %v", ro.Annotations)
+	}
+
+	ro, err = NewRolloutFromDeployment(deploy, DeploymentConversionOptions{GenerateSteps: true, UseWorkloadRef: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ro.Spec.WorkloadRef == nil || ro.Spec.WorkloadRef.Name != "demo" || len(ro.Spec.Template.Spec.Containers) != 0 {
+		t.Errorf("expected a workload reference instead of the template")
+	}
+	if len(ro.Spec.Strategy.Canary.Steps) != len(DefaultCanarySteps()) {
+		t.Errorf("expected the default steps")
+	}
+}
+
+func TestNewDeploymentFromRollout(t *testing.T) {
+	ro, err := NewRolloutFromDeployment(newTestDeployment(), DeploymentConversionOptions{GenerateSteps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ro.Spec.Strategy.Canary.CanaryMetadata = &PodTemplateMetadata{Labels: map[string]string{"role": "canary"}}
+	ro.Spec.Template.Spec.Containers[0].Image = "nginx:1.24"
+
+	deploy, report, err := NewDeploymentFromRollout(ro, DeploymentExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deploy.Spec.Template.Spec.Containers[0].Image != "nginx:1.24" {
+		t.Errorf("expected the rollout template")
+	}
+	rollingUpdate := deploy.Spec.Strategy.RollingUpdate
+	if rollingUpdate == nil || rollingUpdate.MaxSurge.String() != "50%" || rollingUpdate.MaxUnavailable.String() != "1" {
+		t.Errorf("unexpected rolling update %v", rollingUpdate)
+	}
+	if len(report.Lost) != 2 || report.Lost[0].Path != "spec.strategy.canary.steps" || report.Lost[1].Path != "spec.strategy.canary.canaryMetadata" {
+		t.Errorf("unexpected report %v", report.Lost)
+	}
+
+	stableRS := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-abc"},
+		Spec: appsv1.ReplicaSetSpec{Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "demo", DefaultRolloutUniqueLabelKey: "abc"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "nginx:1.23"}}},
+		}},
+	}
+	deploy, _, err = NewDeploymentFromRollout(ro, DeploymentExportOptions{UseStableTemplate: true, StableReplicaSet: stableRS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deploy.Spec.Template.Spec.Containers[0].Image != "nginx:1.23" {
+		t.Errorf("expected the stable template")
+	}
+	if _, ok := deploy.Spec.Template.Labels[DefaultRolloutUniqueLabelKey]; ok {
+		t.Errorf("the pod template hash label should be removed")
+	}
+	if _, _, err := NewDeploymentFromRollout(ro, DeploymentExportOptions{UseStableTemplate: true}); err == nil {
+		t.Errorf("expected an error without stable ReplicaSet")
+	}
+
+	// a rollout referencing its workload has no selector, the selector of the stable ReplicaSet is used
+	ref := ro.DeepCopy()
+	ref.Spec.Selector = nil
+	ref.Spec.Template = corev1.PodTemplateSpec{}
+	ref.Spec.WorkloadRef = &ObjectRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "demo"}
+	if _, _, err := NewDeploymentFromRollout(ref, DeploymentExportOptions{UseStableTemplate: true, StableReplicaSet: stableRS}); err == nil {
+		t.Errorf("expected an error without selector")
+	}
+	stableRS.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo", DefaultRolloutUniqueLabelKey: "abc"}}
+	deploy, _, err = NewDeploymentFromRollout(ref, DeploymentExportOptions{UseStableTemplate: true, StableReplicaSet: stableRS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deploy.Spec.Selector == nil || len(deploy.Spec.Selector.MatchLabels) != 1 || deploy.Spec.Selector.MatchLabels["app"] != "demo" {
+		t.Errorf("expected the stable selector without the pod template hash, got %v", deploy.Spec.Selector)
+	}
+	if stableRS.Spec.Selector.MatchLabels[DefaultRolloutUniqueLabelKey] != "abc" {
+		t.Errorf("the stable ReplicaSet should not be modified")
+	}
+}
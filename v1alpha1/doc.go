@@ -0,0 +1,3 @@
+// Package v1alpha1 is the v1alpha1 version of the Rollout API, the hub version of the conversions.
+// +groupName=argoproj.io
+package v1alpha1
@@ -0,0 +1,365 @@
+// Package fake provides an in-memory implementation of the Rollout client and lister, to be used in tests.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/chamhaw/kubernetes-rollout-api/apply"
+	applyv1alpha1 "github.com/chamhaw/kubernetes-rollout-api/applyconfiguration/v1alpha1"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+type objectKey struct {
+	clusterCode string
+	namespace   string
+	name        string
+}
+
+// Clientset is an in-memory RolloutsGetter. Rollouts are stored per clusterCode, and Update keeps the
+// stored status while UpdateStatus only changes the status, like the API server does for the status subresource.
+// Apply and ApplyStatus merge the configuration into the stored rollout without tracking field ownership.
+type Clientset struct {
+	mu              sync.RWMutex
+	rollouts        map[objectKey]*v1alpha1.Rollout
+	resourceVersion int
+	broadcaster     *watch.Broadcaster
+}
+
+var _ v1alpha1.RolloutsGetter = &Clientset{}
+
+// NewClientset returns a Clientset containing the given rollouts in the cluster clusterCode.
+func NewClientset(clusterCode string, rollouts ...*v1alpha1.Rollout) *Clientset {
+	c := &Clientset{
+		rollouts:    map[objectKey]*v1alpha1.Rollout{},
+		broadcaster: watch.NewBroadcaster(100, watch.DropIfChannelFull),
+	}
+	for _, ro := range rollouts {
+		c.Add(clusterCode, ro)
+	}
+	return c
+}
+
+// Add stores a copy of the rollout in the cluster clusterCode without emitting a watch event.
+func (c *Clientset) Add(clusterCode string, rollout *v1alpha1.Rollout) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ro := rollout.DeepCopy()
+	c.resourceVersion++
+	ro.ResourceVersion = strconv.Itoa(c.resourceVersion)
+	c.rollouts[objectKey{clusterCode, ro.Namespace, ro.Name}] = ro
+}
+
+// Rollouts returns the RolloutInterface of the namespace.
+func (c *Clientset) Rollouts(namespace string) v1alpha1.RolloutInterface {
+	return &rollouts{client: c, namespace: namespace}
+}
+
+// Lister returns a RolloutLister over the rollouts of the cluster clusterCode.
+func (c *Clientset) Lister(clusterCode string) v1alpha1.RolloutLister {
+	return &rolloutLister{client: c, clusterCode: clusterCode}
+}
+
+func (c *Clientset) list(clusterCode, namespace string, selector labels.Selector) []*v1alpha1.Rollout {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var ret []*v1alpha1.Rollout
+	for key, ro := range c.rollouts {
+		if key.clusterCode != clusterCode || (namespace != metav1.NamespaceAll && key.namespace != namespace) {
+			continue
+		}
+		if !selector.Matches(labels.Set(ro.Labels)) {
+			continue
+		}
+		ret = append(ret, ro.DeepCopy())
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Namespace != ret[j].Namespace {
+			return ret[i].Namespace < ret[j].Namespace
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+type rollouts struct {
+	client    *Clientset
+	namespace string
+}
+
+var (
+	_ apply.RolloutApplier                = &rollouts{}
+	_ v1alpha1.RolloutListWatcher         = &rollouts{}
+	_ v1alpha1.RolloutPreconditionDeleter = &rollouts{}
+)
+
+func (r *rollouts) namespaceOr(namespace string) string {
+	if namespace == "" {
+		return r.namespace
+	}
+	return namespace
+}
+
+func (r *rollouts) Create(ctx context.Context, clusterCode string, rollout *v1alpha1.Rollout) (*v1alpha1.Rollout, error) {
+	c := r.client
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ro := rollout.DeepCopy()
+	ro.Namespace = r.namespaceOr(ro.Namespace)
+	key := objectKey{clusterCode, ro.Namespace, ro.Name}
+	if _, ok := c.rollouts[key]; ok {
+		return nil, errors.NewAlreadyExists(v1alpha1.Resource(v1alpha1.RolloutPlural), ro.Name)
+	}
+	return c.create(key, ro), nil
+}
+
+// create must be called with the lock held
+func (c *Clientset) create(key objectKey, ro *v1alpha1.Rollout) *v1alpha1.Rollout {
+	c.resourceVersion++
+	ro.ResourceVersion = strconv.Itoa(c.resourceVersion)
+	ro.Generation = 1
+	ro.CreationTimestamp = metav1.Now()
+	c.rollouts[key] = ro
+	c.emit(key.clusterCode, watch.Added, ro)
+	return ro.DeepCopy()
+}
+
+func (r *rollouts) Update(ctx context.Context, clusterCode string, rollout *v1alpha1.Rollout) (*v1alpha1.Rollout, error) {
+	return r.update(clusterCode, rollout, false)
+}
+
+func (r *rollouts) UpdateStatus(ctx context.Context, clusterCode string, rollout *v1alpha1.Rollout) (*v1alpha1.Rollout, error) {
+	return r.update(clusterCode, rollout, true)
+}
+
+func (r *rollouts) update(clusterCode string, rollout *v1alpha1.Rollout, status bool) (*v1alpha1.Rollout, error) {
+	c := r.client
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := objectKey{clusterCode, r.namespaceOr(rollout.Namespace), rollout.Name}
+	existing, ok := c.rollouts[key]
+	if !ok {
+		return nil, errors.NewNotFound(v1alpha1.Resource(v1alpha1.RolloutPlural), rollout.Name)
+	}
+	if rollout.ResourceVersion != "" && rollout.ResourceVersion != existing.ResourceVersion {
+		return nil, errors.NewConflict(v1alpha1.Resource(v1alpha1.RolloutPlural), rollout.Name, nil)
+	}
+
+	var ro *v1alpha1.Rollout
+	if status {
+		ro = existing.DeepCopy()
+		rollout.Status.DeepCopyInto(&ro.Status)
+	} else {
+		ro = rollout.DeepCopy()
+		ro.Namespace = key.namespace
+		ro.CreationTimestamp = existing.CreationTimestamp
+		ro.Generation = existing.Generation
+		existing.Status.DeepCopyInto(&ro.Status)
+		if !equality.Semantic.DeepEqual(existing.Spec, ro.Spec) {
+			ro.Generation++
+		}
+	}
+	c.resourceVersion++
+	ro.ResourceVersion = strconv.Itoa(c.resourceVersion)
+	c.rollouts[key] = ro
+	c.emit(clusterCode, watch.Modified, ro)
+	return ro.DeepCopy(), nil
+}
+
+func (r *rollouts) Apply(ctx context.Context, clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration, opts metav1.ApplyOptions) (*v1alpha1.Rollout, error) {
+	return r.apply(clusterCode, rollout, opts, false)
+}
+
+func (r *rollouts) ApplyStatus(ctx context.Context, clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration, opts metav1.ApplyOptions) (*v1alpha1.Rollout, error) {
+	return r.apply(clusterCode, rollout, opts, true)
+}
+
+// apply merges the configuration into the stored rollout with a strategic merge patch. Unlike the API server,
+// it does not track the fields owned by each manager, so fields removed from a configuration are kept.
+func (r *rollouts) apply(clusterCode string, rollout *applyv1alpha1.RolloutApplyConfiguration, opts metav1.ApplyOptions, status bool) (*v1alpha1.Rollout, error) {
+	if rollout == nil || rollout.Name == nil {
+		return nil, errors.NewBadRequest("rollout provided to Apply must not be nil and must have a name")
+	}
+	if opts.FieldManager == "" {
+		return nil, errors.NewBadRequest("fieldManager is required for apply requests")
+	}
+	patch, err := json.Marshal(rollout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := r.client
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	namespace := ""
+	if rollout.Namespace != nil {
+		namespace = *rollout.Namespace
+	}
+	key := objectKey{clusterCode, r.namespaceOr(namespace), *rollout.Name}
+	existing, ok := c.rollouts[key]
+	if !ok {
+		if status {
+			return nil, errors.NewNotFound(v1alpha1.Resource(v1alpha1.RolloutPlural), key.name)
+		}
+		ro := &v1alpha1.Rollout{}
+		if err := json.Unmarshal(patch, ro); err != nil {
+			return nil, errors.NewBadRequest(err.Error())
+		}
+		ro.Namespace = key.namespace
+		ro.Status = v1alpha1.RolloutStatus{}
+		return c.create(key, ro), nil
+	}
+
+	original, err := json.Marshal(existing)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := strategicpatch.StrategicMergePatch(original, patch, v1alpha1.Rollout{})
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+	applied := &v1alpha1.Rollout{}
+	if err := json.Unmarshal(merged, applied); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	ro := existing.DeepCopy()
+	if status {
+		ro.Status = applied.Status
+	} else {
+		ro.Labels, ro.Annotations, ro.OwnerReferences, ro.Finalizers = applied.Labels, applied.Annotations, applied.OwnerReferences, applied.Finalizers
+		ro.Spec = applied.Spec
+		if !equality.Semantic.DeepEqual(existing.Spec, ro.Spec) {
+			ro.Generation++
+		}
+	}
+	if equality.Semantic.DeepEqual(existing, ro) {
+		return ro, nil
+	}
+	c.resourceVersion++
+	ro.ResourceVersion = strconv.Itoa(c.resourceVersion)
+	c.rollouts[key] = ro
+	c.emit(clusterCode, watch.Modified, ro)
+	return ro.DeepCopy(), nil
+}
+
+func (r *rollouts) Delete(ctx context.Context, clusterCode, namespace, name string) (*v1alpha1.Rollout, error) {
+	return r.delete(clusterCode, namespace, name, metav1.Preconditions{})
+}
+
+func (r *rollouts) DeleteWithPreconditions(ctx context.Context, clusterCode, namespace, name string, preconditions metav1.Preconditions) (*v1alpha1.Rollout, error) {
+	return r.delete(clusterCode, namespace, name, preconditions)
+}
+
+func (r *rollouts) delete(clusterCode, namespace, name string, preconditions metav1.Preconditions) (*v1alpha1.Rollout, error) {
+	c := r.client
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := objectKey{clusterCode, r.namespaceOr(namespace), name}
+	ro, ok := c.rollouts[key]
+	if !ok {
+		return nil, errors.NewNotFound(v1alpha1.Resource(v1alpha1.RolloutPlural), name)
+	}
+	if (preconditions.UID != nil && *preconditions.UID != ro.UID) ||
+		(preconditions.ResourceVersion != nil && *preconditions.ResourceVersion != ro.ResourceVersion) {
+		return nil, errors.NewConflict(v1alpha1.Resource(v1alpha1.RolloutPlural), name, nil)
+	}
+	delete(c.rollouts, key)
+	c.emit(clusterCode, watch.Deleted, ro)
+	return ro.DeepCopy(), nil
+}
+
+func (r *rollouts) Get(ctx context.Context, clusterCode, namespace, name string) (*v1alpha1.Rollout, error) {
+	c := r.client
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ro, ok := c.rollouts[objectKey{clusterCode, r.namespaceOr(namespace), name}]
+	if !ok {
+		return nil, errors.NewNotFound(v1alpha1.Resource(v1alpha1.RolloutPlural), name)
+	}
+	return ro.DeepCopy(), nil
+}
+
+func (r *rollouts) List(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (*v1alpha1.RolloutList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+	list := &v1alpha1.RolloutList{}
+	for _, ro := range r.client.list(clusterCode, r.namespaceOr(namespace), selector) {
+		list.Items = append(list.Items, *ro)
+	}
+	return list, nil
+}
+
+func (r *rollouts) Watch(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+	namespace = r.namespaceOr(namespace)
+	w, err := r.client.broadcaster.Watch()
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		event := in.Object.(*clusterEvent)
+		ro := event.Rollout
+		if event.clusterCode != clusterCode || (namespace != metav1.NamespaceAll && ro.Namespace != namespace) {
+			return in, false
+		}
+		if !selector.Matches(labels.Set(ro.Labels)) {
+			return in, false
+		}
+		return watch.Event{Type: in.Type, Object: ro.DeepCopy()}, true
+	}), nil
+}
+
+// clusterEvent carries the cluster of a rollout through the broadcaster
+type clusterEvent struct {
+	*v1alpha1.Rollout
+	clusterCode string
+}
+
+// emit must be called with the lock held
+func (c *Clientset) emit(clusterCode string, eventType watch.EventType, ro *v1alpha1.Rollout) {
+	ro = ro.DeepCopy()
+	_ = c.broadcaster.Action(eventType, &clusterEvent{Rollout: ro, clusterCode: clusterCode})
+}
+
+type rolloutLister struct {
+	client      *Clientset
+	clusterCode string
+}
+
+func (l *rolloutLister) List(selector labels.Selector) ([]*v1alpha1.Rollout, error) {
+	return l.client.list(l.clusterCode, metav1.NamespaceAll, selector), nil
+}
+
+func (l *rolloutLister) Rollouts(namespace string) v1alpha1.RolloutNamespaceLister {
+	return &rolloutNamespaceLister{client: l.client, clusterCode: l.clusterCode, namespace: namespace}
+}
+
+type rolloutNamespaceLister struct {
+	client      *Clientset
+	clusterCode string
+	namespace   string
+}
+
+func (l *rolloutNamespaceLister) List(selector labels.Selector) ([]*v1alpha1.Rollout, error) {
+	return l.client.list(l.clusterCode, l.namespace, selector), nil
+}
+
+func (l *rolloutNamespaceLister) Get(name string) (*v1alpha1.Rollout, error) {
+	return l.client.Rollouts(l.namespace).Get(context.Background(), l.clusterCode, l.namespace, name)
+}
@@ -0,0 +1,63 @@
+package v1alpha1
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/fnv"
+
+	"github.com/davecgh/go-spew/spew"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// ComputePodTemplateHash returns a hash value calculated from pod template and a collisionCount to avoid hash
+// collision. The algorithm is the one the Kubernetes deployment controller uses, so the result can be used as
+// the DefaultRolloutUniqueLabelKey label value and as the ReplicaSet name suffix.
+func ComputePodTemplateHash(template *corev1.PodTemplateSpec, collisionCount *int32) string {
+	podTemplateSpecHasher := fnv.New32a()
+	deepHashObject(podTemplateSpecHasher, *template)
+
+	// Add collisionCount in the hash if it exists.
+	if collisionCount != nil {
+		collisionCountBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint32(collisionCountBytes, uint32(*collisionCount))
+		podTemplateSpecHasher.Write(collisionCountBytes)
+	}
+
+	return rand.SafeEncodeString(fmt.Sprint(podTemplateSpecHasher.Sum32()))
+}
+
+// ComputeStepHash returns a hash value calculated from the canary steps, which is stored in Status.CurrentStepHash.
+// The steps are hashed in their JSON form, so the result does not depend on the field order of the manifest
+// they were decoded from. An empty string is returned if there is no canary strategy.
+func ComputeStepHash(canary *CanaryStrategy) string {
+	if canary == nil {
+		return ""
+	}
+	rolloutStepHasher := fnv.New32a()
+	stepsBytes, err := json.Marshal(canary.Steps)
+	if err != nil {
+		panic(err)
+	}
+	_, err = rolloutStepHasher.Write(stepsBytes)
+	if err != nil {
+		panic(err)
+	}
+	return rand.SafeEncodeString(fmt.Sprint(rolloutStepHasher.Sum32()))
+}
+
+// deepHashObject writes specified object to hash using the spew library
+// which follows pointers and prints actual values of the nested objects
+// ensuring the hash does not change when a pointer changes.
+func deepHashObject(hasher hash.Hash, objectToWrite interface{}) {
+	hasher.Reset()
+	printer := spew.ConfigState{
+		Indent:         " ",
+		SortKeys:       true,
+		DisableMethods: true,
+		SpewKeys:       true,
+	}
+	printer.Fprintf(hasher, "%#v", objectToWrite)
+}
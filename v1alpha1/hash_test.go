@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComputePodTemplateHash(t *testing.T) {
+	template := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "demo", "tier": "web"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "nginx:1.23"}}},
+	}
+	hash := ComputePodTemplateHash(template, nil)
+	if hash != ComputePodTemplateHash(template.DeepCopy(), nil) {
+		t.Errorf("hash of an identical template should be identical")
+	}
+
+	collisionCount := int32(1)
+	if hash == ComputePodTemplateHash(template, &collisionCount) {
+		t.Errorf("collisionCount should change the hash")
+	}
+
+	template.Spec.Containers[0].Image = "nginx:1.24"
+	if hash == ComputePodTemplateHash(template, nil) {
+		t.Errorf("a template change should change the hash")
+	}
+}
+
+func TestComputeStepHash(t *testing.T) {
+	var a, b CanaryStrategy
+	if err := json.Unmarshal([]byte(`{"steps":[{"setWeight":20,"pause":{"duration":"1m"}},{"setCanaryScale":{"weight":50,"replicas":2}}]}`), &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"steps":[{"pause":{"duration":"1m"},"setWeight":20},{"setCanaryScale":{"replicas":2,"weight":50}}]}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	if ComputeStepHash(&a) != ComputeStepHash(&b) {
+		t.Errorf("step hash should not depend on the field order")
+	}
+
+	*b.Steps[0].SetWeight = 30
+	if ComputeStepHash(&a) == ComputeStepHash(&b) {
+		t.Errorf("a step change should change the hash")
+	}
+	if ComputeStepHash(nil) != "" {
+		t.Errorf("step hash of a nil canary strategy should be empty")
+	}
+}
@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RevisionAnnotation is the revision annotation of a rollout's replica sets which records its rollout sequence
+	RevisionAnnotation = "rollout.argoproj.io/revision"
+	// DefaultRevisionHistoryLimit is the number of old ReplicaSets retained when RevisionHistoryLimit is not set
+	DefaultRevisionHistoryLimit = int32(10)
+)
+
+// GetRevision returns the revision number of the object from its RevisionAnnotation.
+func GetRevision(obj metav1.Object) (int64, error) {
+	value, ok := obj.GetAnnotations()[RevisionAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// GetRevisionHistoryLimit returns the number of old ReplicaSets to retain.
+func GetRevisionHistoryLimit(rollout *Rollout) int32 {
+	if rollout.Spec.RevisionHistoryLimit != nil {
+		return *rollout.Spec.RevisionHistoryLimit
+	}
+	return DefaultRevisionHistoryLimit
+}
+
+// GetReplicaSetsToDelete returns the old ReplicaSets exceeding the revision history limit, in the order they
+// should be deleted (oldest revision first). Only the oldest ReplicaSets beyond the limit are candidates: the new
+// ReplicaSet (Status.CurrentPodHash) is not counted, while the stable ReplicaSet (Status.StableRS) and ReplicaSets
+// which still have replicas are counted but never deleted, so fewer ReplicaSets may be returned than exceed the
+// limit. ReplicaSets with an invalid revision are considered the oldest.
+func GetReplicaSetsToDelete(rollout *Rollout, rss []*appsv1.ReplicaSet) []*appsv1.ReplicaSet {
+	var oldRSs []*appsv1.ReplicaSet
+	for _, rs := range rss {
+		if rs.DeletionTimestamp != nil {
+			continue
+		}
+		if isReplicaSetHash(rs, rollout.Status.CurrentPodHash) {
+			continue
+		}
+		oldRSs = append(oldRSs, rs)
+	}
+
+	diff := len(oldRSs) - int(GetRevisionHistoryLimit(rollout))
+	if diff <= 0 {
+		return nil
+	}
+
+	sort.SliceStable(oldRSs, func(i, j int) bool {
+		revI, _ := GetRevision(oldRSs[i])
+		revJ, _ := GetRevision(oldRSs[j])
+		if revI != revJ {
+			return revI < revJ
+		}
+		if !oldRSs[i].CreationTimestamp.Equal(&oldRSs[j].CreationTimestamp) {
+			return oldRSs[i].CreationTimestamp.Before(&oldRSs[j].CreationTimestamp)
+		}
+		return oldRSs[i].Name < oldRSs[j].Name
+	})
+
+	var toDelete []*appsv1.ReplicaSet
+	for _, rs := range oldRSs[:diff] {
+		if isReplicaSetHash(rs, rollout.Status.StableRS) {
+			continue
+		}
+		if rs.Status.Replicas != 0 || (rs.Spec.Replicas != nil && *rs.Spec.Replicas != 0) {
+			continue
+		}
+		toDelete = append(toDelete, rs)
+	}
+	return toDelete
+}
+
+// isReplicaSetHash returns true if the ReplicaSet has the given pod template hash.
+func isReplicaSetHash(rs *appsv1.ReplicaSet, podHash string) bool {
+	return podHash != "" && rs.Labels[DefaultRolloutUniqueLabelKey] == podHash
+}
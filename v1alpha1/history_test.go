@@ -0,0 +1,64 @@
+package v1alpha1
+
+import (
+	"strconv"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newHistoryReplicaSet(hash string, revision string, replicas int32) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "demo-" + hash,
+			Labels:      map[string]string{DefaultRolloutUniqueLabelKey: hash},
+			Annotations: map[string]string{RevisionAnnotation: revision},
+		},
+		Spec: appsv1.ReplicaSetSpec{Replicas: &replicas},
+	}
+}
+
+func TestGetReplicaSetsToDelete(t *testing.T) {
+	limit := int32(2)
+	ro := &Rollout{
+		Spec:   RolloutSpec{RevisionHistoryLimit: &limit},
+		Status: RolloutStatus{CurrentPodHash: "new", StableRS: "stable"},
+	}
+	rss := []*appsv1.ReplicaSet{
+		newHistoryReplicaSet("new", "7", 3),
+		newHistoryReplicaSet("stable", "1", 3),
+		newHistoryReplicaSet("r6", "6", 0),
+		newHistoryReplicaSet("r5", "5", 0),
+		newHistoryReplicaSet("r4", "4", 1),
+		newHistoryReplicaSet("r3", "3", 0),
+		newHistoryReplicaSet("invalid", "x", 0),
+	}
+
+	var names []string
+	for _, rs := range GetReplicaSetsToDelete(ro, rss) {
+		names = append(names, rs.Labels[DefaultRolloutUniqueLabelKey])
+	}
+	expected := []string{"invalid", "r3"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	}
+
+	limit = 10
+	if toDelete := GetReplicaSetsToDelete(ro, rss); len(toDelete) != 0 {
+		t.Errorf("expected nothing to delete, got %d ReplicaSets", len(toDelete))
+	}
+
+	ro.Spec.RevisionHistoryLimit = nil
+	for i := 0; i < 12; i++ {
+		rss = append(rss, newHistoryReplicaSet("old"+strconv.Itoa(i), strconv.Itoa(100+i), 0))
+	}
+	if toDelete := GetReplicaSetsToDelete(ro, rss); len(toDelete) != 6 {
+		t.Errorf("expected 6 ReplicaSets to delete with the default limit, got %d", len(toDelete))
+	}
+}
@@ -0,0 +1,230 @@
+package v1alpha1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// InstanceIDRequirement returns the label requirement matching the objects of a controller instance.
+// The default instance (empty instanceID) only matches objects without the LabelKeyControllerInstanceID label.
+func InstanceIDRequirement(instanceID string) (*labels.Requirement, error) {
+	if instanceID == "" {
+		return labels.NewRequirement(LabelKeyControllerInstanceID, selection.DoesNotExist, nil)
+	}
+	return labels.NewRequirement(LabelKeyControllerInstanceID, selection.Equals, []string{instanceID})
+}
+
+// InstanceIDSelector adds the instance ID requirement to the selector. A nil selector selects everything.
+func InstanceIDSelector(selector labels.Selector, instanceID string) (labels.Selector, error) {
+	req, err := InstanceIDRequirement(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if selector == nil {
+		selector = labels.Everything()
+	}
+	return selector.Add(*req), nil
+}
+
+// AddInstanceIDToListOptions adds the instance ID requirement to the label selector of the list options.
+func AddInstanceIDToListOptions(opts *metav1.ListOptions, instanceID string) error {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return err
+	}
+	selector, err = InstanceIDSelector(selector, instanceID)
+	if err != nil {
+		return err
+	}
+	opts.LabelSelector = selector.String()
+	return nil
+}
+
+// SetInstanceIDLabel stamps the instance ID label on an object created by a controller instance.
+// The label is removed for the default instance.
+func SetInstanceIDLabel(obj metav1.Object, instanceID string) {
+	objLabels := obj.GetLabels()
+	if instanceID == "" {
+		if _, ok := objLabels[LabelKeyControllerInstanceID]; ok {
+			delete(objLabels, LabelKeyControllerInstanceID)
+			obj.SetLabels(objLabels)
+		}
+		return
+	}
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	objLabels[LabelKeyControllerInstanceID] = instanceID
+	obj.SetLabels(objLabels)
+}
+
+// MatchesInstanceID returns true if the object belongs to the controller instance. Like InstanceIDRequirement,
+// the default instance only matches objects without the label, not objects with an empty label.
+func MatchesInstanceID(obj metav1.Object, instanceID string) bool {
+	value, ok := obj.GetLabels()[LabelKeyControllerInstanceID]
+	if instanceID == "" {
+		return !ok
+	}
+	return value == instanceID
+}
+
+// NewInstanceIDRolloutsGetter returns a RolloutsGetter restricted to the Rollouts of a controller instance:
+// created and updated Rollouts are stamped with the instance ID label, List and Watch only return the Rollouts
+// of the instance, and Get, Update, UpdateStatus and Delete return a NotFound error for Rollouts of another
+// instance. The returned RolloutInterfaces implement RolloutListWatcher if those of getter do.
+func NewInstanceIDRolloutsGetter(getter RolloutsGetter, instanceID string) RolloutsGetter {
+	return &instanceIDRolloutsGetter{getter: getter, instanceID: instanceID}
+}
+
+type instanceIDRolloutsGetter struct {
+	getter     RolloutsGetter
+	instanceID string
+}
+
+func (g *instanceIDRolloutsGetter) Rollouts(namespace string) RolloutInterface {
+	rollouts := &instanceIDRollouts{RolloutInterface: g.getter.Rollouts(namespace), instanceID: g.instanceID}
+	if listWatcher, ok := ListWatcherFor(rollouts.RolloutInterface); ok {
+		return &instanceIDRolloutListWatcher{instanceIDRollouts: rollouts, listWatcher: listWatcher}
+	}
+	return rollouts
+}
+
+type instanceIDRollouts struct {
+	RolloutInterface
+	instanceID string
+}
+
+func (c *instanceIDRollouts) Create(ctx context.Context, clusterCode string, rollout *Rollout) (*Rollout, error) {
+	rollout = rollout.DeepCopy()
+	SetInstanceIDLabel(rollout, c.instanceID)
+	return c.RolloutInterface.Create(ctx, clusterCode, rollout)
+}
+
+// Update and UpdateStatus write the rollout at the resource version which was checked, unless the caller set
+// one, so that the write fails with a Conflict if the rollout moved to another instance in between.
+func (c *instanceIDRollouts) Update(ctx context.Context, clusterCode string, rollout *Rollout) (*Rollout, error) {
+	rollout, err := c.checked(ctx, clusterCode, rollout)
+	if err != nil {
+		return nil, err
+	}
+	SetInstanceIDLabel(rollout, c.instanceID)
+	return c.RolloutInterface.Update(ctx, clusterCode, rollout)
+}
+
+func (c *instanceIDRollouts) UpdateStatus(ctx context.Context, clusterCode string, rollout *Rollout) (*Rollout, error) {
+	rollout, err := c.checked(ctx, clusterCode, rollout)
+	if err != nil {
+		return nil, err
+	}
+	return c.RolloutInterface.UpdateStatus(ctx, clusterCode, rollout)
+}
+
+// checked returns a copy of the rollout to write, with the resource version of the stored rollout if it
+// belongs to the instance.
+func (c *instanceIDRollouts) checked(ctx context.Context, clusterCode string, rollout *Rollout) (*Rollout, error) {
+	existing, err := c.Get(ctx, clusterCode, rollout.Namespace, rollout.Name)
+	if err != nil {
+		return nil, err
+	}
+	rollout = rollout.DeepCopy()
+	if rollout.ResourceVersion == "" {
+		rollout.ResourceVersion = existing.ResourceVersion
+	}
+	return rollout, nil
+}
+
+// Delete deletes the rollout with the UID and resource version which were checked as preconditions when the
+// RolloutInterface implements RolloutPreconditionDeleter.
+func (c *instanceIDRollouts) Delete(ctx context.Context, clusterCode, namespace, name string) (*Rollout, error) {
+	existing, err := c.Get(ctx, clusterCode, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if deleter, ok := c.RolloutInterface.(RolloutPreconditionDeleter); ok {
+		return deleter.DeleteWithPreconditions(ctx, clusterCode, namespace, name,
+			metav1.Preconditions{UID: &existing.UID, ResourceVersion: &existing.ResourceVersion})
+	}
+	return c.RolloutInterface.Delete(ctx, clusterCode, namespace, name)
+}
+
+func (c *instanceIDRollouts) Get(ctx context.Context, clusterCode, namespace, name string) (*Rollout, error) {
+	rollout, err := c.RolloutInterface.Get(ctx, clusterCode, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if !MatchesInstanceID(rollout, c.instanceID) {
+		return nil, errors.NewNotFound(Resource(RolloutPlural), name)
+	}
+	return rollout, nil
+}
+
+// instanceIDRolloutListWatcher is an instanceIDRollouts over a RolloutInterface which can list and watch.
+type instanceIDRolloutListWatcher struct {
+	*instanceIDRollouts
+	listWatcher RolloutListWatcher
+}
+
+func (c *instanceIDRolloutListWatcher) List(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (*RolloutList, error) {
+	if err := AddInstanceIDToListOptions(&opts, c.instanceID); err != nil {
+		return nil, err
+	}
+	return c.listWatcher.List(ctx, clusterCode, namespace, opts)
+}
+
+func (c *instanceIDRolloutListWatcher) Watch(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	if err := AddInstanceIDToListOptions(&opts, c.instanceID); err != nil {
+		return nil, err
+	}
+	return c.listWatcher.Watch(ctx, clusterCode, namespace, opts)
+}
+
+// NewInstanceIDRolloutLister returns a RolloutLister which only lists the Rollouts of a controller instance.
+func NewInstanceIDRolloutLister(lister RolloutLister, instanceID string) RolloutLister {
+	return &instanceIDRolloutLister{lister: lister, instanceID: instanceID}
+}
+
+type instanceIDRolloutLister struct {
+	lister     RolloutLister
+	instanceID string
+}
+
+func (l *instanceIDRolloutLister) List(selector labels.Selector) ([]*Rollout, error) {
+	selector, err := InstanceIDSelector(selector, l.instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return l.lister.List(selector)
+}
+
+func (l *instanceIDRolloutLister) Rollouts(namespace string) RolloutNamespaceLister {
+	return &instanceIDRolloutNamespaceLister{lister: l.lister.Rollouts(namespace), instanceID: l.instanceID}
+}
+
+type instanceIDRolloutNamespaceLister struct {
+	lister     RolloutNamespaceLister
+	instanceID string
+}
+
+func (l *instanceIDRolloutNamespaceLister) List(selector labels.Selector) ([]*Rollout, error) {
+	selector, err := InstanceIDSelector(selector, l.instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return l.lister.List(selector)
+}
+
+func (l *instanceIDRolloutNamespaceLister) Get(name string) (*Rollout, error) {
+	rollout, err := l.lister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if !MatchesInstanceID(rollout, l.instanceID) {
+		return nil, errors.NewNotFound(Resource(RolloutPlural), name)
+	}
+	return rollout, nil
+}
@@ -0,0 +1,192 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1/fake"
+)
+
+func newInstanceRollout(name, instanceID string) *v1alpha1.Rollout {
+	ro := &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	v1alpha1.SetInstanceIDLabel(ro, instanceID)
+	return ro
+}
+
+func TestInstanceIDRolloutsGetter(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset("dev", newInstanceRollout("default-instance", ""), newInstanceRollout("other-instance", "other"))
+
+	for _, test := range []struct {
+		instanceID string
+		expected   string
+	}{
+		{instanceID: "", expected: "default-instance"},
+		{instanceID: "other", expected: "other-instance"},
+	} {
+		rollouts := v1alpha1.NewInstanceIDRolloutsGetter(client, test.instanceID).Rollouts("default")
+		listWatcher, ok := v1alpha1.ListWatcherFor(rollouts)
+		if !ok {
+			t.Fatalf("instance %q: expected the rollouts to list and watch", test.instanceID)
+		}
+		list, err := listWatcher.List(ctx, "dev", "default", metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list.Items) != 1 || list.Items[0].Name != test.expected {
+			t.Errorf("instance %q: expected only %s, got %v", test.instanceID, test.expected, list.Items)
+		}
+		if _, err := rollouts.Get(ctx, "dev", "default", test.expected); err != nil {
+			t.Errorf("instance %q: unexpected error %v", test.instanceID, err)
+		}
+
+		lister := v1alpha1.NewInstanceIDRolloutLister(client.Lister("dev"), test.instanceID)
+		listed, err := lister.Rollouts("default").List(labels.Everything())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listed) != 1 || listed[0].Name != test.expected {
+			t.Errorf("instance %q: expected lister to list only %s, got %v", test.instanceID, test.expected, listed)
+		}
+	}
+
+	rollouts := v1alpha1.NewInstanceIDRolloutsGetter(client, "other").Rollouts("default")
+	listWatcher, _ := v1alpha1.ListWatcherFor(rollouts)
+	w, err := listWatcher.Watch(ctx, "dev", "default", metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	if _, err := client.Rollouts("default").Create(ctx, "dev", newInstanceRollout("ignored", "")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rollouts.Get(ctx, "dev", "default", "default-instance"); !errors.IsNotFound(err) {
+		t.Errorf("expected NotFound for a rollout of another instance, got %v", err)
+	}
+	created, err := rollouts.Create(ctx, "dev", &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Name: "created"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Labels[v1alpha1.LabelKeyControllerInstanceID] != "other" {
+		t.Errorf("created rollout should be stamped with the instance ID, got labels %v", created.Labels)
+	}
+	event := <-w.ResultChan()
+	if event.Object.(*v1alpha1.Rollout).Name != "created" {
+		t.Errorf("expected a watch event for the created rollout only, got %v", event.Object)
+	}
+}
+
+func TestInstanceIDRolloutsWrite(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset("dev", newInstanceRollout("default-instance", ""), newInstanceRollout("other-instance", "other"))
+	rollouts := v1alpha1.NewInstanceIDRolloutsGetter(client, "other").Rollouts("default")
+
+	foreign := newInstanceRollout("default-instance", "")
+	if _, err := rollouts.Update(ctx, "dev", foreign); !errors.IsNotFound(err) {
+		t.Errorf("expected NotFound updating a rollout of another instance, got %v", err)
+	}
+	if _, err := rollouts.UpdateStatus(ctx, "dev", foreign); !errors.IsNotFound(err) {
+		t.Errorf("expected NotFound updating the status of a rollout of another instance, got %v", err)
+	}
+	if _, err := rollouts.Delete(ctx, "dev", "default", "default-instance"); !errors.IsNotFound(err) {
+		t.Errorf("expected NotFound deleting a rollout of another instance, got %v", err)
+	}
+	if _, err := client.Rollouts("default").Get(ctx, "dev", "default", "default-instance"); err != nil {
+		t.Errorf("the rollout of another instance should not be deleted: %v", err)
+	}
+
+	// an update cannot move a rollout to another instance
+	own := newInstanceRollout("other-instance", "")
+	updated, err := rollouts.Update(ctx, "dev", own)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Labels[v1alpha1.LabelKeyControllerInstanceID] != "other" {
+		t.Errorf("updated rollout should keep the instance ID, got labels %v", updated.Labels)
+	}
+	if _, err := rollouts.Delete(ctx, "dev", "default", "other-instance"); err != nil {
+		t.Errorf("unexpected error deleting a rollout of the instance: %v", err)
+	}
+}
+
+// racingRollouts moves the rollouts it gets to another instance, as a concurrent write would after the check
+type racingRollouts struct {
+	v1alpha1.RolloutInterface
+}
+
+func (r *racingRollouts) Rollouts(namespace string) v1alpha1.RolloutInterface {
+	return r
+}
+
+func (r *racingRollouts) Get(ctx context.Context, clusterCode, namespace, name string) (*v1alpha1.Rollout, error) {
+	ro, err := r.RolloutInterface.Get(ctx, clusterCode, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	moved := ro.DeepCopy()
+	v1alpha1.SetInstanceIDLabel(moved, "moved")
+	if _, err := r.RolloutInterface.Update(ctx, clusterCode, moved); err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
+
+func (r *racingRollouts) DeleteWithPreconditions(ctx context.Context, clusterCode, namespace, name string, preconditions metav1.Preconditions) (*v1alpha1.Rollout, error) {
+	return r.RolloutInterface.(v1alpha1.RolloutPreconditionDeleter).DeleteWithPreconditions(ctx, clusterCode, namespace, name, preconditions)
+}
+
+func TestInstanceIDRolloutsConcurrentWrite(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset("dev", newInstanceRollout("web", "other"))
+	racing := &racingRollouts{RolloutInterface: client.Rollouts("default")}
+	rollouts := v1alpha1.NewInstanceIDRolloutsGetter(racing, "other").Rollouts("default")
+	if _, ok := v1alpha1.ListWatcherFor(rollouts); ok {
+		t.Errorf("expected the rollouts not to list and watch when the wrapped rollouts do not")
+	}
+
+	if _, err := rollouts.Update(ctx, "dev", newInstanceRollout("web", "other")); !errors.IsConflict(err) {
+		t.Errorf("expected a Conflict updating a rollout moved to another instance, got %v", err)
+	}
+	client.Add("dev", newInstanceRollout("web", "other"))
+	if _, err := rollouts.Delete(ctx, "dev", "default", "web"); !errors.IsConflict(err) {
+		t.Errorf("expected a Conflict deleting a rollout moved to another instance, got %v", err)
+	}
+	stored, err := client.Rollouts("default").Get(ctx, "dev", "default", "web")
+	if err != nil {
+		t.Fatalf("the rollout moved to another instance should not be deleted: %v", err)
+	}
+	if stored.Labels[v1alpha1.LabelKeyControllerInstanceID] != "moved" {
+		t.Errorf("unexpected labels %v", stored.Labels)
+	}
+}
+
+func TestMatchesInstanceID(t *testing.T) {
+	for _, test := range []struct {
+		labels     map[string]string
+		instanceID string
+		expected   bool
+	}{
+		{labels: nil, instanceID: "", expected: true},
+		{labels: map[string]string{v1alpha1.LabelKeyControllerInstanceID: ""}, instanceID: "", expected: false},
+		{labels: map[string]string{v1alpha1.LabelKeyControllerInstanceID: "other"}, instanceID: "", expected: false},
+		{labels: map[string]string{v1alpha1.LabelKeyControllerInstanceID: "other"}, instanceID: "other", expected: true},
+		{labels: nil, instanceID: "other", expected: false},
+	} {
+		ro := &v1alpha1.Rollout{ObjectMeta: metav1.ObjectMeta{Labels: test.labels}}
+		if actual := v1alpha1.MatchesInstanceID(ro, test.instanceID); actual != test.expected {
+			t.Errorf("labels %v, instance %q: expected %t, got %t", test.labels, test.instanceID, test.expected, actual)
+		}
+		req, err := v1alpha1.InstanceIDRequirement(test.instanceID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := req.Matches(labels.Set(test.labels)); actual != test.expected {
+			t.Errorf("labels %v, instance %q: expected the requirement to match %t, got %t", test.labels, test.instanceID, test.expected, actual)
+		}
+	}
+}
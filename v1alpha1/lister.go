@@ -0,0 +1,26 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// RolloutLister helps list Rollouts.
+// All objects returned here must be treated as read-only.
+type RolloutLister interface {
+	// List lists all Rollouts in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*Rollout, err error)
+	// Rollouts returns an object that can list and get Rollouts.
+	Rollouts(namespace string) RolloutNamespaceLister
+}
+
+// RolloutNamespaceLister helps list and get Rollouts.
+// All objects returned here must be treated as read-only.
+type RolloutNamespaceLister interface {
+	// List lists all Rollouts in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*Rollout, err error)
+	// Get retrieves the Rollout from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*Rollout, error)
+}
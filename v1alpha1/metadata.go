@@ -0,0 +1,179 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const (
+	// DefaultEphemeralMetadataAnnotationKey is the annotation attached to a ReplicaSet recording the
+	// CanaryMetadata or StableMetadata currently applied to its pod template, so it can be removed later
+	// even if the Rollout spec changed in between.
+	DefaultEphemeralMetadataAnnotationKey = "ephemeral-metadata"
+)
+
+// EphemeralMetadataPatch holds the CanaryMetadata/StableMetadata changes to apply to the pod template of a
+// ReplicaSet (and to its existing pods).
+type EphemeralMetadataPatch struct {
+	// ReplicaSet is the ReplicaSet the patch applies to
+	ReplicaSet *appsv1.ReplicaSet
+	// Desired is the ephemeral metadata the ReplicaSet should carry, nil if none
+	Desired *PodTemplateMetadata
+	// Add holds the labels and annotations to set
+	Add PodTemplateMetadata
+	// RemoveLabels holds the keys of the labels to remove
+	RemoveLabels []string
+	// RemoveAnnotations holds the keys of the annotations to remove
+	RemoveAnnotations []string
+}
+
+// Empty returns true if the patch does not change anything.
+func (p *EphemeralMetadataPatch) Empty() bool {
+	return len(p.Add.Labels) == 0 && len(p.Add.Annotations) == 0 &&
+		len(p.RemoveLabels) == 0 && len(p.RemoveAnnotations) == 0
+}
+
+// GetEphemeralMetadataPatches returns the ephemeral metadata patches of the new and the stable ReplicaSets.
+// While a canary is in flight (the new ReplicaSet is not the stable one and the phase is not Healthy),
+// the new ReplicaSet carries CanaryMetadata and the stable ReplicaSet StableMetadata. Once the rollout is
+// fully promoted, the new ReplicaSet acts as the stable one, so its CanaryMetadata is replaced by StableMetadata.
+// Patches that do not change anything are omitted.
+func GetEphemeralMetadataPatches(rollout *Rollout, newRS, stableRS *appsv1.ReplicaSet, phase RolloutPhase) []EphemeralMetadataPatch {
+	canary := rollout.Spec.Strategy.Canary
+	if canary == nil {
+		return nil
+	}
+	promoted := phase == RolloutPhaseHealthy || (newRS != nil && stableRS != nil && newRS.Name == stableRS.Name)
+
+	var patches []EphemeralMetadataPatch
+	if newRS != nil {
+		desired := canary.CanaryMetadata
+		if promoted {
+			desired = canary.StableMetadata
+		}
+		patch := newEphemeralMetadataPatch(rollout, newRS, desired)
+		if !patch.Empty() {
+			patches = append(patches, patch)
+		}
+	}
+	if stableRS != nil && (newRS == nil || newRS.Name != stableRS.Name) {
+		patch := newEphemeralMetadataPatch(rollout, stableRS, canary.StableMetadata)
+		if !patch.Empty() {
+			patches = append(patches, patch)
+		}
+	}
+	return patches
+}
+
+// ApplyEphemeralMetadataPatch returns a copy of the patched ReplicaSet, with the patch applied to the pod
+// template and the applied metadata recorded in the DefaultEphemeralMetadataAnnotationKey annotation.
+func ApplyEphemeralMetadataPatch(patch EphemeralMetadataPatch) *appsv1.ReplicaSet {
+	rs := patch.ReplicaSet.DeepCopy()
+	template := &rs.Spec.Template
+	for _, key := range patch.RemoveLabels {
+		delete(template.Labels, key)
+	}
+	for _, key := range patch.RemoveAnnotations {
+		delete(template.Annotations, key)
+	}
+	for key, value := range patch.Add.Labels {
+		if template.Labels == nil {
+			template.Labels = map[string]string{}
+		}
+		template.Labels[key] = value
+	}
+	for key, value := range patch.Add.Annotations {
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[key] = value
+	}
+
+	if patch.Desired == nil || (len(patch.Desired.Labels) == 0 && len(patch.Desired.Annotations) == 0) {
+		delete(rs.Annotations, DefaultEphemeralMetadataAnnotationKey)
+		return rs
+	}
+	recorded, _ := json.Marshal(patch.Desired)
+	if rs.Annotations == nil {
+		rs.Annotations = map[string]string{}
+	}
+	rs.Annotations[DefaultEphemeralMetadataAnnotationKey] = string(recorded)
+	return rs
+}
+
+func newEphemeralMetadataPatch(rollout *Rollout, rs *appsv1.ReplicaSet, desired *PodTemplateMetadata) EphemeralMetadataPatch {
+	patch := EphemeralMetadataPatch{ReplicaSet: rs, Desired: desired}
+	template := rs.Spec.Template
+	var desiredLabels, desiredAnnotations map[string]string
+	if desired != nil {
+		desiredLabels = desired.Labels
+		desiredAnnotations = desired.Annotations
+	}
+
+	for key, value := range desiredLabels {
+		if current, ok := template.Labels[key]; !ok || current != value {
+			if patch.Add.Labels == nil {
+				patch.Add.Labels = map[string]string{}
+			}
+			patch.Add.Labels[key] = value
+		}
+	}
+	for key, value := range desiredAnnotations {
+		if current, ok := template.Annotations[key]; !ok || current != value {
+			if patch.Add.Annotations == nil {
+				patch.Add.Annotations = map[string]string{}
+			}
+			patch.Add.Annotations[key] = value
+		}
+	}
+
+	// the recorded metadata no longer desired is removed, or set back to the value of the pod template of the
+	// rollout if it defines the key too
+	base := rollout.Spec.Template
+	applied := appliedEphemeralMetadata(rs)
+	for key := range applied.Labels {
+		if _, ok := desiredLabels[key]; ok {
+			continue
+		}
+		if value, ok := base.Labels[key]; ok {
+			if template.Labels[key] != value {
+				if patch.Add.Labels == nil {
+					patch.Add.Labels = map[string]string{}
+				}
+				patch.Add.Labels[key] = value
+			}
+		} else if _, ok := template.Labels[key]; ok {
+			patch.RemoveLabels = append(patch.RemoveLabels, key)
+		}
+	}
+	for key := range applied.Annotations {
+		if _, ok := desiredAnnotations[key]; ok {
+			continue
+		}
+		if value, ok := base.Annotations[key]; ok {
+			if template.Annotations[key] != value {
+				if patch.Add.Annotations == nil {
+					patch.Add.Annotations = map[string]string{}
+				}
+				patch.Add.Annotations[key] = value
+			}
+		} else if _, ok := template.Annotations[key]; ok {
+			patch.RemoveAnnotations = append(patch.RemoveAnnotations, key)
+		}
+	}
+	sort.Strings(patch.RemoveLabels)
+	sort.Strings(patch.RemoveAnnotations)
+	return patch
+}
+
+// appliedEphemeralMetadata returns the ephemeral metadata recorded on the ReplicaSet, empty if it has no
+// record: only recorded keys are known to have been added for the rollout.
+func appliedEphemeralMetadata(rs *appsv1.ReplicaSet) PodTemplateMetadata {
+	applied := PodTemplateMetadata{}
+	if recorded, ok := rs.Annotations[DefaultEphemeralMetadataAnnotationKey]; ok {
+		_ = json.Unmarshal([]byte(recorded), &applied)
+	}
+	return applied
+}
@@ -0,0 +1,131 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func newMetadataRollout() *Rollout {
+	return &Rollout{
+		Spec: RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}},
+			Strategy: RolloutStrategy{Canary: &CanaryStrategy{
+				CanaryMetadata: &PodTemplateMetadata{Labels: map[string]string{"role": "canary"}},
+				StableMetadata: &PodTemplateMetadata{Labels: map[string]string{"role": "stable"}, Annotations: map[string]string{"stable": "true"}},
+			}},
+		},
+	}
+}
+
+func newMetadataReplicaSet(name string, labels map[string]string) *appsv1.ReplicaSet {
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	rs.Spec.Template.Labels = labels
+	return rs
+}
+
+func TestGetEphemeralMetadataPatches(t *testing.T) {
+	ro := newMetadataRollout()
+	newRS := newMetadataReplicaSet("demo-new", map[string]string{"app": "demo"})
+	stableRS := newMetadataReplicaSet("demo-stable", map[string]string{"app": "demo"})
+
+	patches := GetEphemeralMetadataPatches(ro, newRS, stableRS, RolloutPhaseProgressing)
+	if len(patches) != 2 {
+		t.Fatalf("expected 2 patches, got %d", len(patches))
+	}
+	if patches[0].Add.Labels["role"] != "canary" || patches[1].Add.Labels["role"] != "stable" {
+		t.Errorf("unexpected patches %v", patches)
+	}
+	newRS = ApplyEphemeralMetadataPatch(patches[0])
+	stableRS = ApplyEphemeralMetadataPatch(patches[1])
+	if len(GetEphemeralMetadataPatches(ro, newRS, stableRS, RolloutPhaseProgressing)) != 0 {
+		t.Errorf("applied patches should leave nothing to do")
+	}
+
+	// the canary is promoted: the new ReplicaSet becomes stable
+	patches = GetEphemeralMetadataPatches(ro, newRS, newRS, RolloutPhaseHealthy)
+	if len(patches) != 1 {
+		t.Fatalf("expected 1 patch, got %d", len(patches))
+	}
+	promoted := ApplyEphemeralMetadataPatch(patches[0])
+	expectedLabels := map[string]string{"app": "demo", "role": "stable"}
+	if !reflect.DeepEqual(promoted.Spec.Template.Labels, expectedLabels) {
+		t.Errorf("expected labels %v, got %v", expectedLabels, promoted.Spec.Template.Labels)
+	}
+	if promoted.Spec.Template.Annotations["stable"] != "true" {
+		t.Errorf("expected the stable annotation to be added")
+	}
+
+	// the canary metadata is dropped from the spec, the recorded one is still removed
+	ro.Spec.Strategy.Canary.CanaryMetadata = nil
+	patches = GetEphemeralMetadataPatches(ro, newRS, stableRS, RolloutPhaseProgressing)
+	if len(patches) != 1 || !reflect.DeepEqual(patches[0].RemoveLabels, []string{"role"}) {
+		t.Errorf("expected the role label to be removed, got %v", patches)
+	}
+}
+
+func TestEphemeralMetadataWithoutRecord(t *testing.T) {
+	ro := newMetadataRollout()
+	ro.Spec.Strategy.Canary.StableMetadata = nil
+	// the role label of the stable ReplicaSet was not added for the rollout
+	stableRS := newMetadataReplicaSet("demo-stable", map[string]string{"app": "demo", "role": "canary"})
+	newRS := newMetadataReplicaSet("demo-new", map[string]string{"app": "demo"})
+
+	patches := GetEphemeralMetadataPatches(ro, newRS, stableRS, RolloutPhaseProgressing)
+	if len(patches) != 1 || patches[0].ReplicaSet.Name != "demo-new" {
+		t.Errorf("expected only the new ReplicaSet to be patched, got %v", patches)
+	}
+	if template := stablePodTemplate(ro, stableRS); template.Labels["role"] != "canary" {
+		t.Errorf("expected the labels without record to be kept, got %v", template.Labels)
+	}
+}
+
+func TestEphemeralMetadataRestoresTemplate(t *testing.T) {
+	ro := newMetadataRollout()
+	ro.Spec.Template.Labels = map[string]string{"app": "demo", "role": "web"}
+	newRS := newMetadataReplicaSet("demo-new", map[string]string{"app": "demo", "role": "web"})
+
+	patches := GetEphemeralMetadataPatches(ro, newRS, nil, RolloutPhaseProgressing)
+	if len(patches) != 1 || patches[0].Add.Labels["role"] != "canary" {
+		t.Fatalf("expected the canary role to be added, got %v", patches)
+	}
+	newRS = ApplyEphemeralMetadataPatch(patches[0])
+	if template := stablePodTemplate(ro, newRS); template.Labels["role"] != "web" {
+		t.Errorf("expected the role label of the template, got %v", template.Labels)
+	}
+
+	// the canary metadata is dropped from the spec, the label of the template is set back
+	ro.Spec.Strategy.Canary.CanaryMetadata = nil
+	patches = GetEphemeralMetadataPatches(ro, newRS, nil, RolloutPhaseProgressing)
+	if len(patches) != 1 || len(patches[0].RemoveLabels) != 0 || patches[0].Add.Labels["role"] != "web" {
+		t.Fatalf("expected the role label of the template to be restored, got %v", patches)
+	}
+	restored := ApplyEphemeralMetadataPatch(patches[0])
+	expectedLabels := map[string]string{"app": "demo", "role": "web"}
+	if !reflect.DeepEqual(restored.Spec.Template.Labels, expectedLabels) {
+		t.Errorf("expected labels %v, got %v", expectedLabels, restored.Spec.Template.Labels)
+	}
+	if _, ok := restored.Annotations[DefaultEphemeralMetadataAnnotationKey]; ok {
+		t.Errorf("expected the record to be removed")
+	}
+}
+
+func TestValidateEphemeralMetadata(t *testing.T) {
+	ro := newMetadataRollout()
+	fldPath := field.NewPath("spec", "strategy", "canary")
+	if errs := ValidateEphemeralMetadata(ro, fldPath); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	ro.Spec.Strategy.Canary.CanaryMetadata.Labels["app"] = "canary"
+	ro.Spec.Strategy.Canary.StableMetadata.Labels[DefaultRolloutUniqueLabelKey] = "abc"
+	errs := ValidateEphemeralMetadata(ro, fldPath)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errs[0].Field != "spec.strategy.canary.canaryMetadata.labels[app]" {
+		t.Errorf("unexpected field %s", errs[0].Field)
+	}
+}
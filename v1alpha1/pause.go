@@ -0,0 +1,59 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StepPause describes the pause of the current canary step at a given moment.
+type StepPause struct {
+	// Indefinite is true when the pause step has no duration, the rollout only resumes when a user promotes it.
+	Indefinite bool
+	// Deadline is the time a timed pause ends. It is nil for an indefinite pause, or when the controller
+	// has not yet recorded the CanaryPauseStep pause condition.
+	Deadline *metav1.Time
+	// Remaining is the time left before Deadline. It is 0 once the deadline has passed, and the full
+	// pause duration when the pause has not started yet.
+	Remaining time.Duration
+}
+
+// GetPauseCondition returns the pause condition with the given reason, nil if there is none.
+func (s *RolloutStatus) GetPauseCondition(reason PauseReason) *PauseCondition {
+	for i := range s.PauseConditions {
+		if s.PauseConditions[i].Reason == reason {
+			return &s.PauseConditions[i]
+		}
+	}
+	return nil
+}
+
+// GetCurrentStepPause returns the pause state of the current canary step. The second return value is false
+// when the current step is not a pause step.
+// A duration that DurationSeconds cannot parse (-1) is handled like the controller does: the deadline is
+// already reached once the pause started.
+func GetCurrentStepPause(rollout *Rollout, now time.Time) (StepPause, bool) {
+	step, _ := GetCurrentCanaryStep(rollout)
+	if step == nil || step.Pause == nil {
+		return StepPause{}, false
+	}
+	if step.Pause.Duration == nil {
+		return StepPause{Indefinite: true}, true
+	}
+
+	duration := time.Duration(step.Pause.DurationSeconds()) * time.Second
+	cond := rollout.Status.GetPauseCondition(PauseReasonCanaryPauseStep)
+	if cond == nil {
+		if duration < 0 {
+			duration = 0
+		}
+		return StepPause{Remaining: duration}, true
+	}
+
+	deadline := metav1.NewTime(cond.StartTime.Add(duration))
+	remaining := deadline.Sub(now)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return StepPause{Deadline: &deadline, Remaining: remaining}, true
+}
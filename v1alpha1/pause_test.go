@@ -0,0 +1,89 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPauseRollout(pause *RolloutPause, conditions ...PauseCondition) *Rollout {
+	index := int32(1)
+	weight := int32(20)
+	return &Rollout{
+		Spec: RolloutSpec{
+			Strategy: RolloutStrategy{
+				Canary: &CanaryStrategy{
+					Steps: []CanaryStep{{SetWeight: &weight}, {Pause: pause}},
+				},
+			},
+		},
+		Status: RolloutStatus{
+			CurrentStepIndex: &index,
+			PauseConditions:  conditions,
+		},
+	}
+}
+
+func TestGetCurrentStepPause(t *testing.T) {
+	now := time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+	started := PauseCondition{Reason: PauseReasonCanaryPauseStep, StartTime: metav1.NewTime(now.Add(-time.Minute))}
+
+	tests := []struct {
+		name       string
+		rollout    *Rollout
+		ok         bool
+		indefinite bool
+		deadline   *time.Time
+		remaining  time.Duration
+	}{
+		{
+			name:    "not a pause step",
+			rollout: newPauseRollout(nil),
+		},
+		{
+			name:       "indefinite",
+			rollout:    newPauseRollout(&RolloutPause{}, started),
+			ok:         true,
+			indefinite: true,
+		},
+		{
+			name:      "not started",
+			rollout:   newPauseRollout(&RolloutPause{Duration: DurationFromString("5m")}),
+			ok:        true,
+			remaining: 5 * time.Minute,
+		},
+		{
+			name:      "timed",
+			rollout:   newPauseRollout(&RolloutPause{Duration: DurationFromString("5m")}, started),
+			ok:        true,
+			deadline:  func() *time.Time { d := now.Add(4 * time.Minute); return &d }(),
+			remaining: 4 * time.Minute,
+		},
+		{
+			name:      "expired",
+			rollout:   newPauseRollout(&RolloutPause{Duration: DurationFromInt(30)}, started),
+			ok:        true,
+			deadline:  func() *time.Time { d := now.Add(-30 * time.Second); return &d }(),
+			remaining: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pause, ok := GetCurrentStepPause(test.rollout, now)
+			if ok != test.ok {
+				t.Fatalf("expected ok %v, got %v", test.ok, ok)
+			}
+			if pause.Indefinite != test.indefinite {
+				t.Errorf("expected indefinite %v, got %v", test.indefinite, pause.Indefinite)
+			}
+			if (pause.Deadline == nil) != (test.deadline == nil) ||
+				(pause.Deadline != nil && !pause.Deadline.Time.Equal(*test.deadline)) {
+				t.Errorf("expected deadline %v, got %v", test.deadline, pause.Deadline)
+			}
+			if pause.Remaining != test.remaining {
+				t.Errorf("expected remaining %v, got %v", test.remaining, pause.Remaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// RolloutTimedOutReason is added in a rollout when its newest replica set fails to show any progress
+	// within the given deadline (progressDeadlineSeconds).
+	RolloutTimedOutReason = "ProgressDeadlineExceeded"
+	// RolloutTimedOutMessage is added in a rollout when the rollout fails to show any progress
+	// within the given deadline (progressDeadlineSeconds).
+	RolloutTimedOutMessage = "Rollout %q has timed out progressing."
+)
+
+// GetProgressDeadlineSeconds returns the progress deadline of the rollout.
+func GetProgressDeadlineSeconds(rollout *Rollout) int32 {
+	if rollout.Spec.ProgressDeadlineSeconds != nil {
+		return *rollout.Spec.ProgressDeadlineSeconds
+	}
+	return DefaultProgressDeadlineSeconds
+}
+
+// IsRolloutPaused returns true if the rollout is paused by the user or by the controller.
+func IsRolloutPaused(rollout *Rollout) bool {
+	if rollout.Spec.Paused || len(rollout.Status.PauseConditions) > 0 {
+		return true
+	}
+	cond := GetRolloutCondition(rollout.Status, RolloutPaused)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// RolloutTimedOut returns true if the rollout did not make progress within ProgressDeadlineSeconds.
+// The deadline is counted from the last progress recorded in the Progressing condition, or from the
+// last time the rollout was resumed, so the time spent paused is not counted. A paused, aborted or
+// healthy rollout does not time out.
+func RolloutTimedOut(rollout *Rollout, now time.Time) bool {
+	progressing := GetRolloutCondition(rollout.Status, RolloutProgressing)
+	if progressing == nil {
+		return false
+	}
+	if progressing.Reason == RolloutTimedOutReason {
+		return true
+	}
+	if rollout.Status.Abort || rollout.Status.Phase == RolloutPhaseHealthy || IsRolloutPaused(rollout) {
+		return false
+	}
+
+	from := progressing.LastUpdateTime.Time
+	if paused := GetRolloutCondition(rollout.Status, RolloutPaused); paused != nil && paused.LastTransitionTime.After(from) {
+		from = paused.LastTransitionTime.Time
+	}
+	deadline := time.Duration(GetProgressDeadlineSeconds(rollout)) * time.Second
+	return from.Add(deadline).Before(now)
+}
+
+// NewRolloutTimedOutCondition returns the Progressing condition of a rollout which timed out.
+func NewRolloutTimedOutCondition(rollout *Rollout) *RolloutCondition {
+	msg := fmt.Sprintf(RolloutTimedOutMessage, rollout.Name)
+	return NewRolloutCondition(RolloutProgressing, corev1.ConditionFalse, RolloutTimedOutReason, msg)
+}
+
+// SetRolloutTimedOut records in the status that the rollout timed out: the Progressing condition is set to
+// ProgressDeadlineExceeded and the phase becomes Degraded. The rollout is also aborted if
+// ProgressDeadlineAbort is set.
+func SetRolloutTimedOut(rollout *Rollout) {
+	if rollout.Spec.ProgressDeadlineAbort {
+		Abort(rollout)
+	}
+	cond := NewRolloutTimedOutCondition(rollout)
+	SetRolloutCondition(&rollout.Status, *cond)
+	rollout.Status.Phase = RolloutPhaseDegraded
+	reason := RolloutTimedOutReason
+	if rollout.Status.Abort {
+		reason = RolloutAbortedReason
+	}
+	rollout.Status.Message = fmt.Sprintf("%s: %s", reason, cond.Message)
+}
@@ -0,0 +1,62 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRolloutTimedOut(t *testing.T) {
+	now := time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+	deadline := int32(60)
+	newRollout := func(lastProgress time.Duration, resumed *time.Duration) *Rollout {
+		ro := &Rollout{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo"},
+			Spec:       RolloutSpec{ProgressDeadlineSeconds: &deadline},
+			Status: RolloutStatus{Conditions: []RolloutCondition{{
+				Type:           RolloutProgressing,
+				Status:         corev1.ConditionTrue,
+				LastUpdateTime: metav1.NewTime(now.Add(-lastProgress)),
+			}}},
+		}
+		if resumed != nil {
+			ro.Status.Conditions = append(ro.Status.Conditions, RolloutCondition{
+				Type:               RolloutPaused,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(now.Add(-*resumed)),
+			})
+		}
+		return ro
+	}
+	recentlyResumed := 30 * time.Second
+
+	if RolloutTimedOut(newRollout(30*time.Second, nil), now) {
+		t.Errorf("rollout within its deadline should not time out")
+	}
+	if !RolloutTimedOut(newRollout(2*time.Minute, nil), now) {
+		t.Errorf("rollout past its deadline should time out")
+	}
+	if RolloutTimedOut(newRollout(2*time.Minute, &recentlyResumed), now) {
+		t.Errorf("time spent paused should not count")
+	}
+	paused := newRollout(2*time.Minute, nil)
+	paused.Spec.Paused = true
+	if RolloutTimedOut(paused, now) {
+		t.Errorf("paused rollout should not time out")
+	}
+
+	ro := newRollout(2*time.Minute, nil)
+	ro.Spec.ProgressDeadlineAbort = true
+	SetRolloutTimedOut(ro)
+	if !IsAborted(ro) || ro.Status.Phase != RolloutPhaseDegraded {
+		t.Errorf("rollout should be aborted and degraded, got %s", ro.Status.Phase)
+	}
+	if cond := GetRolloutCondition(ro.Status, RolloutProgressing); cond.Reason != RolloutTimedOutReason {
+		t.Errorf("expected reason %s, got %s", RolloutTimedOutReason, cond.Reason)
+	}
+	if !RolloutTimedOut(ro, now) {
+		t.Errorf("rollout should stay timed out")
+	}
+}
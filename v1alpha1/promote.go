@@ -0,0 +1,41 @@
+package v1alpha1
+
+// Promote moves the rollout forward: a paused rollout is resumed, otherwise the current step is skipped.
+// A full promotion skips all the remaining steps. Spec and status are both modified, the caller persists
+// them with RolloutInterface.Update and RolloutInterface.UpdateStatus.
+func Promote(rollout *Rollout, full bool) {
+	stepCount := int32(0)
+	if rollout.Spec.Strategy.Canary != nil {
+		stepCount = int32(len(rollout.Spec.Strategy.Canary.Steps))
+	}
+	if full {
+		Resume(rollout)
+		rollout.Status.CurrentStepIndex = &stepCount
+		return
+	}
+	if rollout.Spec.Paused || len(rollout.Status.PauseConditions) > 0 {
+		Resume(rollout)
+		return
+	}
+	// a rollout without current step index is at the first step
+	current := int32(0)
+	if rollout.Status.CurrentStepIndex != nil {
+		current = *rollout.Status.CurrentStepIndex
+	}
+	if current < stepCount {
+		next := current + 1
+		rollout.Status.CurrentStepIndex = &next
+	}
+}
+
+// Pause pauses the rollout at its current step.
+func Pause(rollout *Rollout) {
+	rollout.Spec.Paused = true
+}
+
+// Resume resumes a rollout paused by the user or by the controller. The pause conditions are removed but
+// ControllerPause is kept, which tells the controller the pause was manually resumed.
+func Resume(rollout *Rollout) {
+	rollout.Spec.Paused = false
+	rollout.Status.PauseConditions = nil
+}
@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPromote(t *testing.T) {
+	index := int32(1)
+	ro := &Rollout{
+		Spec: RolloutSpec{Strategy: RolloutStrategy{Canary: &CanaryStrategy{
+			Steps: []CanaryStep{weightStep(10), {Pause: &RolloutPause{}}, weightStep(50)},
+		}}},
+		Status: RolloutStatus{
+			CurrentStepIndex: &index,
+			ControllerPause:  true,
+			PauseConditions:  []PauseCondition{{Reason: PauseReasonCanaryPauseStep, StartTime: metav1.Now()}},
+		},
+	}
+
+	Promote(ro, false)
+	if len(ro.Status.PauseConditions) != 0 || !ro.Status.ControllerPause || *ro.Status.CurrentStepIndex != 1 {
+		t.Errorf("expected the pause step to be resumed, got %v", ro.Status)
+	}
+	Promote(ro, false)
+	if *ro.Status.CurrentStepIndex != 2 {
+		t.Errorf("expected the current step to be skipped, got %d", *ro.Status.CurrentStepIndex)
+	}
+
+	Pause(ro)
+	Promote(ro, true)
+	if ro.Spec.Paused || *ro.Status.CurrentStepIndex != 3 {
+		t.Errorf("expected a full promotion, got paused %v at step %d", ro.Spec.Paused, *ro.Status.CurrentStepIndex)
+	}
+
+	ro.Status.CurrentStepIndex = nil
+	Promote(ro, false)
+	if ro.Status.CurrentStepIndex == nil || *ro.Status.CurrentStepIndex != 1 {
+		t.Errorf("expected a rollout without current step index to move to step 1, got %v", ro.Status.CurrentStepIndex)
+	}
+	ro.Spec.Strategy.Canary.Steps = nil
+	ro.Status.CurrentStepIndex = nil
+	Promote(ro, false)
+	if ro.Status.CurrentStepIndex != nil {
+		t.Errorf("expected a rollout without steps to keep no current step index, got %d", *ro.Status.CurrentStepIndex)
+	}
+}
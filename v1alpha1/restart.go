@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RestartPending returns true if Spec.RestartAt requested a restart that is not reflected in Status.RestartedAt yet.
+func RestartPending(rollout *Rollout) bool {
+	restartAt := rollout.Spec.RestartAt
+	if restartAt == nil {
+		return false
+	}
+	restartedAt := rollout.Status.RestartedAt
+	return restartedAt == nil || restartedAt.Before(restartAt)
+}
+
+// PodNeedsRestart returns true if the pod was created before the restart request. Pods that are already
+// terminating are ignored, and no pod needs a restart until RestartAt has passed, as it may be in the future
+// within the tolerated clock skew.
+func PodNeedsRestart(rollout *Rollout, pod *corev1.Pod, now time.Time) bool {
+	restartAt := rollout.Spec.RestartAt
+	if restartAt == nil || pod.DeletionTimestamp != nil || now.Before(restartAt.Time) {
+		return false
+	}
+	return pod.CreationTimestamp.Before(restartAt)
+}
+
+// PodsToRestart returns the pods which were created before the restart request, in the order given.
+func PodsToRestart(rollout *Rollout, pods []*corev1.Pod, now time.Time) []*corev1.Pod {
+	var restart []*corev1.Pod
+	for _, pod := range pods {
+		if PodNeedsRestart(rollout, pod, now) {
+			restart = append(restart, pod)
+		}
+	}
+	return restart
+}
@@ -0,0 +1,63 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestPodsToRestart(t *testing.T) {
+	now := time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+	restartAt := metav1.NewTime(now)
+	ro := &Rollout{Spec: RolloutSpec{RestartAt: &restartAt}}
+
+	newPod := func(name string, created time.Time, deleting bool) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)}}
+		if deleting {
+			deletionTimestamp := metav1.NewTime(now)
+			pod.DeletionTimestamp = &deletionTimestamp
+		}
+		return pod
+	}
+	pods := []*corev1.Pod{
+		newPod("old", now.Add(-time.Hour), false),
+		newPod("terminating", now.Add(-time.Hour), true),
+		newPod("new", now.Add(time.Second), false),
+	}
+	restart := PodsToRestart(ro, pods, now)
+	if len(restart) != 1 || restart[0].Name != "old" {
+		t.Errorf("expected only pod old to be restarted, got %v", restart)
+	}
+	// a restart request in the future within the clock skew waits until RestartAt has passed
+	if restart := PodsToRestart(ro, pods, now.Add(-30*time.Second)); len(restart) != 0 {
+		t.Errorf("expected no pod to be restarted before RestartAt, got %v", restart)
+	}
+	if restart := PodsToRestart(ro, pods, now.Add(time.Minute)); len(restart) != 1 || restart[0].Name != "old" {
+		t.Errorf("expected only pod old to be restarted after RestartAt, got %v", restart)
+	}
+
+	if !RestartPending(ro) {
+		t.Errorf("restart should be pending")
+	}
+	ro.Status.RestartedAt = &restartAt
+	if RestartPending(ro) {
+		t.Errorf("restart should not be pending")
+	}
+}
+
+func TestValidateRestartAt(t *testing.T) {
+	now := time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+	fldPath := field.NewPath("spec", "restartAt")
+
+	inSkew := metav1.NewTime(now.Add(30 * time.Second))
+	if errs := ValidateRestartAt(&inSkew, now, DefaultRestartAtMaxClockSkew, fldPath); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	future := metav1.NewTime(now.Add(2 * time.Minute))
+	if errs := ValidateRestartAt(&future, now, DefaultRestartAtMaxClockSkew, fldPath); len(errs) != 1 {
+		t.Errorf("expected one error, got %v", errs)
+	}
+}
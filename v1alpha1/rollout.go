@@ -2,6 +2,9 @@ package v1alpha1
 
 import (
 	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // RolloutsGetter has a method to return a RolloutInterface.
@@ -18,3 +21,22 @@ type RolloutInterface interface {
 	Delete(ctx context.Context, clusterCode, namespace, name string) (*Rollout, error)
 	Get(ctx context.Context, clusterCode, namespace, name string) (*Rollout, error)
 }
+
+// RolloutListWatcher lists and watches Rollouts. It is kept apart from RolloutInterface so that existing
+// implementations of RolloutInterface do not have to implement it, use ListWatcherFor to get it.
+type RolloutListWatcher interface {
+	List(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (*RolloutList, error)
+	Watch(ctx context.Context, clusterCode, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// ListWatcherFor returns the RolloutListWatcher of a RolloutInterface, false if it can not list and watch.
+func ListWatcherFor(rollouts RolloutInterface) (RolloutListWatcher, bool) {
+	listWatcher, ok := rollouts.(RolloutListWatcher)
+	return listWatcher, ok
+}
+
+// RolloutPreconditionDeleter deletes a Rollout only if its UID and resource version match the preconditions,
+// atomically with the deletion.
+type RolloutPreconditionDeleter interface {
+	DeleteWithPreconditions(ctx context.Context, clusterCode, namespace, name string, preconditions metav1.Preconditions) (*Rollout, error)
+}
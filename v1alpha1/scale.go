@@ -0,0 +1,91 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ComputeHPAReplicas returns the number of replicas exposed through the scale subresource,
+// which is the sum of the replicas of all the ReplicaSets of the rollout.
+func ComputeHPAReplicas(rss []*appsv1.ReplicaSet) int32 {
+	replicas := int32(0)
+	for _, rs := range rss {
+		if rs != nil {
+			replicas += rs.Status.Replicas
+		}
+	}
+	return replicas
+}
+
+// ComputeStatusSelector serialises Spec.Selector into the form stored in Status.Selector.
+func ComputeStatusSelector(rollout *Rollout) (string, error) {
+	if rollout.Spec.Selector == nil {
+		return "", nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(rollout.Spec.Selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid selector of rollout %s: %w", rollout.Name, err)
+	}
+	return selector.String(), nil
+}
+
+// SetScaleStatus sets Status.HPAReplicas and Status.Selector, the status fields of the scale subresource.
+func SetScaleStatus(rollout *Rollout, rss []*appsv1.ReplicaSet) error {
+	selector, err := ComputeStatusSelector(rollout)
+	if err != nil {
+		return err
+	}
+	rollout.Status.Selector = selector
+	rollout.Status.HPAReplicas = ComputeHPAReplicas(rss)
+	return nil
+}
+
+// ToScale returns the autoscaling/v1 Scale of the rollout, as served by the scale subresource.
+func ToScale(rollout *Rollout) *autoscalingv1.Scale {
+	replicas := DefaultReplicas
+	if rollout.Spec.Replicas != nil {
+		replicas = *rollout.Spec.Replicas
+	}
+	return &autoscalingv1.Scale{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: autoscalingv1.SchemeGroupVersion.String(),
+			Kind:       "Scale",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              rollout.Name,
+			Namespace:         rollout.Namespace,
+			UID:               rollout.UID,
+			ResourceVersion:   rollout.ResourceVersion,
+			CreationTimestamp: rollout.CreationTimestamp,
+		},
+		Spec: autoscalingv1.ScaleSpec{
+			Replicas: replicas,
+		},
+		Status: autoscalingv1.ScaleStatus{
+			Replicas: rollout.Status.HPAReplicas,
+			Selector: rollout.Status.Selector,
+		},
+	}
+}
+
+// ApplyScale returns a copy of the rollout with the desired replicas of the Scale. The Scale must refer
+// to the rollout, and its resource version, when set, must match the one of the rollout.
+func ApplyScale(rollout *Rollout, scale *autoscalingv1.Scale) (*Rollout, error) {
+	if scale.Name != rollout.Name || scale.Namespace != rollout.Namespace {
+		return nil, fmt.Errorf("scale %s/%s does not refer to rollout %s/%s", scale.Namespace, scale.Name, rollout.Namespace, rollout.Name)
+	}
+	if scale.ResourceVersion != "" && scale.ResourceVersion != rollout.ResourceVersion {
+		return nil, fmt.Errorf("scale of rollout %s/%s is outdated: resource version %s, expected %s",
+			rollout.Namespace, rollout.Name, scale.ResourceVersion, rollout.ResourceVersion)
+	}
+	if scale.Spec.Replicas < 0 {
+		return nil, fmt.Errorf("invalid replicas %d for rollout %s/%s", scale.Spec.Replicas, rollout.Namespace, rollout.Name)
+	}
+	rollout = rollout.DeepCopy()
+	replicas := scale.Spec.Replicas
+	rollout.Spec.Replicas = &replicas
+	return rollout, nil
+}
@@ -0,0 +1,118 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// DefaultScaleDownDelaySeconds is the default delay before an old stable ReplicaSet is scaled down
+	DefaultScaleDownDelaySeconds = int32(30)
+)
+
+// GetScaleDownDeadline returns the deadline stored in the DefaultReplicaSetScaleDownDeadlineAnnotationKey
+// annotation. A nil deadline is returned if the annotation is not set.
+func GetScaleDownDeadline(rs *appsv1.ReplicaSet) (*metav1.Time, error) {
+	value, ok := rs.Annotations[DefaultReplicaSetScaleDownDeadlineAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("annotation %s of ReplicaSet %s is empty", DefaultReplicaSetScaleDownDeadlineAnnotationKey, rs.Name)
+	}
+	deadline, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse annotation %s of ReplicaSet %s: %w", DefaultReplicaSetScaleDownDeadlineAnnotationKey, rs.Name, err)
+	}
+	t := metav1.NewTime(deadline)
+	return &t, nil
+}
+
+// RemoveScaleDownDeadline returns a copy of the ReplicaSet without the scale down deadline annotation.
+func RemoveScaleDownDeadline(rs *appsv1.ReplicaSet) *appsv1.ReplicaSet {
+	rs = rs.DeepCopy()
+	delete(rs.Annotations, DefaultReplicaSetScaleDownDeadlineAnnotationKey)
+	return rs
+}
+
+// ScaleDownPlan is the classification of ReplicaSets according to their scale down deadline.
+type ScaleDownPlan struct {
+	// ScaleDownNow holds the ReplicaSets whose deadline has passed, or whose deadline cannot be parsed
+	ScaleDownNow []*appsv1.ReplicaSet
+	// ScaleDownLater holds the ReplicaSets whose deadline is in the future
+	ScaleDownLater []*appsv1.ReplicaSet
+	// Keep holds the ReplicaSets without deadline, or already scaled down
+	Keep []*appsv1.ReplicaSet
+	// RequeueAfter is the time until the earliest deadline of ScaleDownLater, 0 if it is empty
+	RequeueAfter time.Duration
+}
+
+// ScaleDownDeadlines manages the scale down deadline of old stable ReplicaSets.
+type ScaleDownDeadlines struct {
+	// Delay is the time an old stable ReplicaSet is kept up after the rollout transitioned to a new version
+	Delay time.Duration
+	// Clock is used to get the current time
+	Clock clock.PassiveClock
+}
+
+// NewScaleDownDeadlines returns a ScaleDownDeadlines with the given delay using the real clock.
+// A negative delay uses DefaultScaleDownDelaySeconds.
+func NewScaleDownDeadlines(delay time.Duration) *ScaleDownDeadlines {
+	if delay < 0 {
+		delay = time.Duration(DefaultScaleDownDelaySeconds) * time.Second
+	}
+	return &ScaleDownDeadlines{Delay: delay, Clock: clock.RealClock{}}
+}
+
+// Stamp returns a copy of the ReplicaSet annotated with a scale down deadline of now plus Delay.
+// A valid deadline which is already set is kept so repeated calls do not postpone the scale down.
+func (s *ScaleDownDeadlines) Stamp(rs *appsv1.ReplicaSet) *appsv1.ReplicaSet {
+	if deadline, err := GetScaleDownDeadline(rs); err == nil && deadline != nil {
+		return rs.DeepCopy()
+	}
+	rs = rs.DeepCopy()
+	if rs.Annotations == nil {
+		rs.Annotations = map[string]string{}
+	}
+	deadline := s.Clock.Now().Add(s.Delay).UTC()
+	rs.Annotations[DefaultReplicaSetScaleDownDeadlineAnnotationKey] = deadline.Format(time.RFC3339)
+	return rs
+}
+
+// Classify sorts the ReplicaSets into the ones to scale down now, the ones to scale down later and
+// the ones to keep.
+func (s *ScaleDownDeadlines) Classify(rss []*appsv1.ReplicaSet) ScaleDownPlan {
+	plan := ScaleDownPlan{}
+	now := s.Clock.Now()
+	for _, rs := range rss {
+		if rs.Spec.Replicas != nil && *rs.Spec.Replicas == 0 {
+			plan.Keep = append(plan.Keep, rs)
+			continue
+		}
+		deadline, err := GetScaleDownDeadline(rs)
+		if err != nil {
+			plan.ScaleDownNow = append(plan.ScaleDownNow, rs)
+			continue
+		}
+		if deadline == nil {
+			plan.Keep = append(plan.Keep, rs)
+			continue
+		}
+		remaining := deadline.Sub(now)
+		if remaining <= 0 {
+			plan.ScaleDownNow = append(plan.ScaleDownNow, rs)
+			continue
+		}
+		plan.ScaleDownLater = append(plan.ScaleDownLater, rs)
+		if plan.RequeueAfter == 0 || remaining < plan.RequeueAfter {
+			plan.RequeueAfter = remaining
+		}
+	}
+	return plan
+}
@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func newScaleDownReplicaSet(name string, replicas int32, deadline string) *appsv1.ReplicaSet {
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+	}
+	if deadline != "" {
+		rs.Annotations = map[string]string{DefaultReplicaSetScaleDownDeadlineAnnotationKey: deadline}
+	}
+	return rs
+}
+
+func TestScaleDownDeadlines(t *testing.T) {
+	now := time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+	fakeClock := testingclock.NewFakePassiveClock(now)
+	deadlines := &ScaleDownDeadlines{Delay: 30 * time.Second, Clock: fakeClock}
+
+	stamped := deadlines.Stamp(newScaleDownReplicaSet("stable", 3, ""))
+	deadline, err := GetScaleDownDeadline(stamped)
+	if err != nil || deadline == nil || !deadline.Time.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("unexpected deadline %v: %v", deadline, err)
+	}
+
+	fakeClock.SetTime(now.Add(10 * time.Second))
+	if restamped := deadlines.Stamp(stamped); restamped.Annotations[DefaultReplicaSetScaleDownDeadlineAnnotationKey] != stamped.Annotations[DefaultReplicaSetScaleDownDeadlineAnnotationKey] {
+		t.Errorf("an existing deadline should not be postponed")
+	}
+
+	rss := []*appsv1.ReplicaSet{
+		stamped,
+		newScaleDownReplicaSet("expired", 3, now.Add(-time.Minute).Format(time.RFC3339)),
+		newScaleDownReplicaSet("invalid", 3, "tomorrow"),
+		newScaleDownReplicaSet("later", 3, " "+now.Add(time.Minute).Format(time.RFC3339)+" "),
+		newScaleDownReplicaSet("no-deadline", 3, ""),
+		newScaleDownReplicaSet("scaled-down", 0, now.Add(-time.Minute).Format(time.RFC3339)),
+	}
+	plan := deadlines.Classify(rss)
+	names := func(rss []*appsv1.ReplicaSet) []string {
+		var names []string
+		for _, rs := range rss {
+			names = append(names, rs.Name)
+		}
+		return names
+	}
+	if got := names(plan.ScaleDownNow); len(got) != 2 || got[0] != "expired" || got[1] != "invalid" {
+		t.Errorf("unexpected ScaleDownNow %v", got)
+	}
+	if got := names(plan.ScaleDownLater); len(got) != 2 || got[0] != "stable" || got[1] != "later" {
+		t.Errorf("unexpected ScaleDownLater %v", got)
+	}
+	if got := names(plan.Keep); len(got) != 2 || got[0] != "no-deadline" || got[1] != "scaled-down" {
+		t.Errorf("unexpected Keep %v", got)
+	}
+	if plan.RequeueAfter != 20*time.Second {
+		t.Errorf("expected requeue after 20s, got %v", plan.RequeueAfter)
+	}
+
+	fakeClock.SetTime(now.Add(30 * time.Second))
+	plan = deadlines.Classify([]*appsv1.ReplicaSet{stamped})
+	if len(plan.ScaleDownNow) != 1 || plan.RequeueAfter != 0 {
+		t.Errorf("stamped ReplicaSet should be scaled down once the deadline is reached")
+	}
+}
@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestScale(t *testing.T) {
+	replicas := int32(4)
+	ro := &Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default", ResourceVersion: "3"},
+		Spec: RolloutSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "demo"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"web"}},
+				},
+			},
+		},
+	}
+	rss := []*appsv1.ReplicaSet{
+		{Status: appsv1.ReplicaSetStatus{Replicas: 3}},
+		{Status: appsv1.ReplicaSetStatus{Replicas: 2}},
+	}
+	if err := SetScaleStatus(ro, rss); err != nil {
+		t.Fatal(err)
+	}
+	if ro.Status.HPAReplicas != 5 || ro.Status.Selector != "app=demo,tier in (web)" {
+		t.Errorf("unexpected scale status %d %q", ro.Status.HPAReplicas, ro.Status.Selector)
+	}
+
+	scale := ToScale(ro)
+	if scale.Spec.Replicas != 4 || scale.Status.Replicas != 5 || scale.Status.Selector != ro.Status.Selector {
+		t.Errorf("unexpected scale %v", scale)
+	}
+
+	scale.Spec.Replicas = 6
+	scaled, err := ApplyScale(ro, scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *scaled.Spec.Replicas != 6 || *ro.Spec.Replicas != 4 {
+		t.Errorf("expected a copy scaled to 6 replicas")
+	}
+
+	scale.ResourceVersion = "2"
+	if _, err := ApplyScale(ro, scale); err == nil {
+		t.Errorf("expected an error for an outdated scale")
+	}
+}
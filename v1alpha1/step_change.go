@@ -0,0 +1,104 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// StepChange classifies how the canary steps changed between two versions of a Rollout.
+type StepChange string
+
+const (
+	// StepChangeNone indicates the steps are unchanged
+	StepChangeNone StepChange = "None"
+	// StepChangeAppended indicates steps were only added after the existing ones
+	StepChangeAppended StepChange = "Appended"
+	// StepChangeModifiedFuture indicates only steps after the current step were modified or removed
+	StepChangeModifiedFuture StepChange = "ModifiedFuture"
+	// StepChangeModifiedPast indicates the current step or a step before it was modified or removed
+	StepChangeModifiedPast StepChange = "ModifiedPast"
+	// StepChangeReordered indicates the steps are the same but in a different order
+	StepChangeReordered StepChange = "Reordered"
+)
+
+// StepChangeResult is the outcome of comparing two canary step lists.
+type StepChangeResult struct {
+	// Change is the classification of the change
+	Change StepChange
+	// Restart is true when the rollout must restart from step 0
+	Restart bool
+	// StepIndex is the step index the rollout should continue at
+	StepIndex int32
+}
+
+// CompareCanarySteps compares the steps of the old and the new canary strategy, and decides whether a rollout
+// currently at currentStepIndex can continue or must restart from step 0. The rollout can continue as long as
+// the steps it already went through, including the current one, are unchanged.
+func CompareCanarySteps(oldCanary, newCanary *CanaryStrategy, currentStepIndex *int32) StepChangeResult {
+	var oldSteps, newSteps []CanaryStep
+	if oldCanary != nil {
+		oldSteps = oldCanary.Steps
+	}
+	if newCanary != nil {
+		newSteps = newCanary.Steps
+	}
+	current := int32(0)
+	if currentStepIndex != nil {
+		current = *currentStepIndex
+	}
+
+	diffIndex := firstStepDiff(oldSteps, newSteps)
+	if diffIndex == len(oldSteps) && diffIndex == len(newSteps) {
+		return StepChangeResult{Change: StepChangeNone, StepIndex: current}
+	}
+
+	result := StepChangeResult{}
+	switch {
+	case len(oldSteps) == len(newSteps) && sameSteps(oldSteps, newSteps):
+		result.Change = StepChangeReordered
+	case diffIndex == len(oldSteps):
+		result.Change = StepChangeAppended
+	case int32(diffIndex) > current:
+		result.Change = StepChangeModifiedFuture
+	default:
+		result.Change = StepChangeModifiedPast
+	}
+
+	if int32(diffIndex) <= current && result.Change != StepChangeAppended {
+		result.Restart = true
+		return result
+	}
+	result.StepIndex = current
+	if int(result.StepIndex) > len(newSteps) {
+		result.StepIndex = int32(len(newSteps))
+	}
+	return result
+}
+
+// firstStepDiff returns the index of the first step that differs, or the length of the shorter list if one is
+// the prefix of the other.
+func firstStepDiff(a, b []CanaryStep) int {
+	i := 0
+	for i < len(a) && i < len(b) && equality.Semantic.DeepEqual(a[i], b[i]) {
+		i++
+	}
+	return i
+}
+
+// sameSteps returns true if a and b contain the same steps regardless of their order.
+func sameSteps(a, b []CanaryStep) bool {
+	used := make([]bool, len(b))
+	for i := range a {
+		found := false
+		for j := range b {
+			if !used[j] && equality.Semantic.DeepEqual(a[i], b[j]) {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func weightStep(weight int32) CanaryStep {
+	return CanaryStep{SetWeight: &weight}
+}
+
+func TestCompareCanarySteps(t *testing.T) {
+	pause := CanaryStep{Pause: &RolloutPause{}}
+	old := &CanaryStrategy{Steps: []CanaryStep{weightStep(10), pause, weightStep(50), pause}}
+
+	tests := []struct {
+		name      string
+		steps     []CanaryStep
+		current   int32
+		change    StepChange
+		restart   bool
+		stepIndex int32
+	}{
+		{
+			name:      "unchanged",
+			steps:     []CanaryStep{weightStep(10), pause, weightStep(50), pause},
+			current:   2,
+			change:    StepChangeNone,
+			stepIndex: 2,
+		},
+		{
+			name:      "appended",
+			steps:     []CanaryStep{weightStep(10), pause, weightStep(50), pause, weightStep(80)},
+			current:   4,
+			change:    StepChangeAppended,
+			stepIndex: 4,
+		},
+		{
+			name:      "modified future",
+			steps:     []CanaryStep{weightStep(10), pause, weightStep(60)},
+			current:   1,
+			change:    StepChangeModifiedFuture,
+			stepIndex: 1,
+		},
+		{
+			name:    "modified current",
+			steps:   []CanaryStep{weightStep(10), {Pause: &RolloutPause{Duration: DurationFromInt(10)}}, weightStep(50), pause},
+			current: 1,
+			change:  StepChangeModifiedPast,
+			restart: true,
+		},
+		{
+			name:    "modified past",
+			steps:   []CanaryStep{weightStep(20), pause, weightStep(50), pause},
+			current: 2,
+			change:  StepChangeModifiedPast,
+			restart: true,
+		},
+		{
+			name:    "reordered past",
+			steps:   []CanaryStep{pause, weightStep(10), weightStep(50), pause},
+			current: 2,
+			change:  StepChangeReordered,
+			restart: true,
+		},
+		{
+			name:      "reordered future",
+			steps:     []CanaryStep{weightStep(10), pause, pause, weightStep(50)},
+			current:   1,
+			change:    StepChangeReordered,
+			stepIndex: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := CompareCanarySteps(old, &CanaryStrategy{Steps: test.steps}, &test.current)
+			if result.Change != test.change {
+				t.Errorf("expected change %s, got %s", test.change, result.Change)
+			}
+			if result.Restart != test.restart {
+				t.Errorf("expected restart %v, got %v", test.restart, result.Restart)
+			}
+			if result.StepIndex != test.stepIndex {
+				t.Errorf("expected step index %d, got %d", test.stepIndex, result.StepIndex)
+			}
+		})
+	}
+}
@@ -48,6 +48,9 @@ type RolloutSpec struct {
 	// Template describes the pods that will be created.
 	// +optional
 	Template corev1.PodTemplateSpec `json:"template,omitempty" protobuf:"bytes,3,opt,name=template"`
+	// WorkloadRef holds a references to a workload that provides Pod template
+	// +optional
+	WorkloadRef *ObjectRef `json:"workloadRef,omitempty" protobuf:"bytes,10,opt,name=workloadRef"`
 	// Minimum number of seconds for which a newly created pod should be ready
 	// without any of its container crashing, for it to be considered available.
 	// Defaults to 0 (pod will be considered available as soon as it is ready)
@@ -60,6 +63,22 @@ type RolloutSpec struct {
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty" protobuf:"varint,6,opt,name=revisionHistoryLimit"`
 	// Paused pauses the rollout at its current step.
 	Paused bool `json:"paused,omitempty" protobuf:"varint,7,opt,name=paused"`
+	// ProgressDeadlineSeconds The maximum time in seconds for a rollout to
+	// make progress before it is considered to be failed. Time spent paused is not counted.
+	// Defaults to 600s.
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty" protobuf:"varint,8,opt,name=progressDeadlineSeconds"`
+	// ProgressDeadlineAbort is whether to abort the update when ProgressDeadlineSeconds
+	// is exceeded.
+	// +optional
+	ProgressDeadlineAbort bool `json:"progressDeadlineAbort,omitempty" protobuf:"varint,12,opt,name=progressDeadlineAbort"`
+	// RestartAt indicates when all the pods of a Rollout should be restarted. Pods created before this time
+	// are restarted.
+	// +optional
+	RestartAt *metav1.Time `json:"restartAt,omitempty" protobuf:"bytes,9,opt,name=restartAt"`
+	// Analysis configuration for the analysis runs and experiments to retain
+	// +optional
+	Analysis *AnalysisRunStrategy `json:"analysis,omitempty" protobuf:"bytes,11,opt,name=analysis"`
 }
 
 func (s *RolloutSpec) SetResolvedSelector(selector *metav1.LabelSelector) {
@@ -313,12 +332,17 @@ const (
 
 // RolloutStatus is the status for a Rollout resource
 type RolloutStatus struct {
+	// Abort 表示 Rollout 被用户中止，会回滚到 stable RS
+	Abort bool `json:"abort,omitempty" protobuf:"varint,1,opt,name=abort"`
 	// PauseConditions 表示 Rollout "自动" 暂停的原因 比如 CanaryPauseStep. 自动意味着列表中的元素是系统添加进去的，比如定时或者遇到 Pause step 等等。
 	// 如果该列表是空的，但是 controllerPause 是 true，则表示是用户手动恢复了 Rollout
 	PauseConditions []PauseCondition `json:"pauseConditions,omitempty" protobuf:"bytes,2,rep,name=pauseConditions"`
 	// ControllerPause 表示 Rollout 被系统"自动"暂停时会标记为true，同时会写入 PauseConditions。 当被系统自动暂停的 Rollout 被用户手动恢复时, PauseConditions 会被清空
 	// 但 ControllerPause 的值还是 true
 	ControllerPause bool `json:"controllerPause,omitempty" protobuf:"varint,3,opt,name=controllerPause"`
+	// AbortedAt 表示 Rollout 被中止的时间
+	// +optional
+	AbortedAt *metav1.Time `json:"abortedAt,omitempty" protobuf:"bytes,4,opt,name=abortedAt"`
 	// CurrentPodHash 表示当前 pod template hash
 	// +optional
 	CurrentPodHash string `json:"currentPodHash,omitempty" protobuf:"bytes,5,opt,name=currentPodHash"`
@@ -364,6 +388,8 @@ type RolloutStatus struct {
 	// TODO 需要确认stable RS在status中的写入时机
 	StableRS string `json:"stableRS,omitempty" protobuf:"bytes,19,opt,name=stableRS"`
 	// RestartedAt 表示该 Rollout 最后一次重启的时间
+	// +optional
+	RestartedAt *metav1.Time `json:"restartedAt,omitempty" protobuf:"bytes,21,opt,name=restartedAt"`
 	// Phase 表示 Rollout 的 Phase. 只有 ObservedGeneration == Metadata.Generation 时才可以拿来用
 	Phase RolloutPhase `json:"phase,omitempty" protobuf:"bytes,22,opt,name=phase,casttype=RolloutPhase"`
 	// Message 是 Phase 的描述信息
@@ -0,0 +1,164 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// DefaultRestartAtMaxClockSkew is the default tolerated clock skew between the client setting
+	// Spec.RestartAt and the validating server.
+	DefaultRestartAtMaxClockSkew = 1 * time.Minute
+)
+
+// ValidateRestartAt checks that restartAt is not further in the future than maxClockSkew.
+func ValidateRestartAt(restartAt *metav1.Time, now time.Time, maxClockSkew time.Duration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if restartAt == nil {
+		return allErrs
+	}
+	if restartAt.Time.After(now.Add(maxClockSkew)) {
+		msg := fmt.Sprintf("restartAt must not be in the future (tolerated clock skew: %s)", maxClockSkew)
+		allErrs = append(allErrs, field.Invalid(fldPath, restartAt, msg))
+	}
+	return allErrs
+}
+
+// ValidateEphemeralMetadata checks that CanaryMetadata and StableMetadata do not set labels used by
+// the selector or the DefaultRolloutUniqueLabelKey, which would make ReplicaSets select each other's pods.
+func ValidateEphemeralMetadata(rollout *Rollout, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	canary := rollout.Spec.Strategy.Canary
+	if canary == nil {
+		return allErrs
+	}
+	selectorKeys := map[string]bool{}
+	if rollout.Spec.Selector != nil {
+		for key := range rollout.Spec.Selector.MatchLabels {
+			selectorKeys[key] = true
+		}
+		for _, expr := range rollout.Spec.Selector.MatchExpressions {
+			selectorKeys[expr.Key] = true
+		}
+	}
+
+	validate := func(md *PodTemplateMetadata, mdPath *field.Path) {
+		if md == nil {
+			return
+		}
+		for _, key := range sortedKeys(md.Labels) {
+			if key == DefaultRolloutUniqueLabelKey {
+				allErrs = append(allErrs, field.Forbidden(mdPath.Child("labels").Key(key), "label is reserved for the pod template hash"))
+			} else if selectorKeys[key] {
+				allErrs = append(allErrs, field.Forbidden(mdPath.Child("labels").Key(key), "label is used by the rollout selector"))
+			}
+		}
+	}
+	validate(canary.CanaryMetadata, fldPath.Child("canaryMetadata"))
+	validate(canary.StableMetadata, fldPath.Child("stableMetadata"))
+	return allErrs
+}
+
+// ValidateRollout checks the spec of a created rollout: the replica and seconds fields are not negative,
+// the selector matches the pod template unless it is resolved from the workloadRef, the canary steps are
+// well formed, the ephemeral metadata does not set selector labels and restartAt is not in the future.
+func ValidateRollout(rollout *Rollout, now time.Time, maxClockSkew time.Duration) field.ErrorList {
+	specPath := field.NewPath("spec")
+	allErrs := validateRolloutSpec(&rollout.Spec, specPath)
+	allErrs = append(allErrs, ValidateEphemeralMetadata(rollout, specPath.Child("strategy", "canary"))...)
+	allErrs = append(allErrs, ValidateRestartAt(rollout.Spec.RestartAt, now, maxClockSkew, specPath.Child("restartAt"))...)
+	return allErrs
+}
+
+// ValidateRolloutUpdate checks the spec of an updated rollout like ValidateRollout, except that restartAt
+// is only checked when it changes, and that the selector is immutable.
+func ValidateRolloutUpdate(rollout, old *Rollout, now time.Time, maxClockSkew time.Duration) field.ErrorList {
+	specPath := field.NewPath("spec")
+	allErrs := validateRolloutSpec(&rollout.Spec, specPath)
+	allErrs = append(allErrs, ValidateEphemeralMetadata(rollout, specPath.Child("strategy", "canary"))...)
+	if !apiequality.Semantic.DeepEqual(rollout.Spec.RestartAt, old.Spec.RestartAt) {
+		allErrs = append(allErrs, ValidateRestartAt(rollout.Spec.RestartAt, now, maxClockSkew, specPath.Child("restartAt"))...)
+	}
+	if rollout.Spec.WorkloadRef == nil && old.Spec.WorkloadRef == nil &&
+		!apiequality.Semantic.DeepEqual(rollout.Spec.Selector, old.Spec.Selector) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("selector"), rollout.Spec.Selector, "field is immutable"))
+	}
+	return allErrs
+}
+
+func validateRolloutSpec(spec *RolloutSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	nonNegative := func(value *int32, path *field.Path) {
+		if value != nil && *value < 0 {
+			allErrs = append(allErrs, field.Invalid(path, *value, "must be greater than or equal to 0"))
+		}
+	}
+	nonNegative(spec.Replicas, fldPath.Child("replicas"))
+	nonNegative(&spec.MinReadySeconds, fldPath.Child("minReadySeconds"))
+	nonNegative(spec.RevisionHistoryLimit, fldPath.Child("revisionHistoryLimit"))
+	if spec.ProgressDeadlineSeconds != nil && *spec.ProgressDeadlineSeconds <= spec.MinReadySeconds {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("progressDeadlineSeconds"), *spec.ProgressDeadlineSeconds,
+			"must be greater than minReadySeconds"))
+	}
+
+	if spec.WorkloadRef == nil {
+		selectorPath := fldPath.Child("selector")
+		if spec.Selector == nil {
+			allErrs = append(allErrs, field.Required(selectorPath, ""))
+		} else if selector, err := metav1.LabelSelectorAsSelector(spec.Selector); err != nil {
+			allErrs = append(allErrs, field.Invalid(selectorPath, spec.Selector, err.Error()))
+		} else if selector.Empty() {
+			allErrs = append(allErrs, field.Invalid(selectorPath, spec.Selector, "empty selector is invalid for rollout"))
+		} else if !selector.Matches(labels.Set(spec.Template.Labels)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("template", "metadata", "labels"), spec.Template.Labels,
+				"`selector` does not match template `labels`"))
+		}
+	}
+
+	if canary := spec.Strategy.Canary; canary != nil {
+		stepsPath := fldPath.Child("strategy", "canary", "steps")
+		for i, step := range canary.Steps {
+			stepPath := stepsPath.Index(i)
+			set := 0
+			if step.SetWeight != nil {
+				set++
+				if *step.SetWeight < 0 || *step.SetWeight > 100 {
+					allErrs = append(allErrs, field.Invalid(stepPath.Child("setWeight"), *step.SetWeight, "must be between 0 and 100"))
+				}
+			}
+			if step.Pause != nil {
+				set++
+				if step.Pause.DurationSeconds() < 0 {
+					allErrs = append(allErrs, field.Invalid(stepPath.Child("pause", "duration"), step.Pause.Duration,
+						"must be a non-negative number of seconds or a duration with a unit"))
+				}
+			}
+			if scale := step.SetCanaryScale; scale != nil {
+				set++
+				if scale.Weight != nil && (*scale.Weight < 0 || *scale.Weight > 100) {
+					allErrs = append(allErrs, field.Invalid(stepPath.Child("setCanaryScale", "weight"), *scale.Weight, "must be between 0 and 100"))
+				}
+				nonNegative(scale.Replicas, stepPath.Child("setCanaryScale", "replicas"))
+			}
+			if set != 1 {
+				allErrs = append(allErrs, field.Invalid(stepPath, step, "exactly one of setWeight, pause and setCanaryScale must be set"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,109 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newValidRollout() *Rollout {
+	weight := int32(50)
+	return &Rollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}}},
+			Strategy: RolloutStrategy{Canary: &CanaryStrategy{
+				Steps: []CanaryStep{{SetWeight: &weight}, {Pause: &RolloutPause{}}, {SetCanaryScale: &SetCanaryScale{Weight: &weight}}},
+			}},
+		},
+	}
+}
+
+func TestValidateRollout(t *testing.T) {
+	now := time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+	negative := int32(-1)
+	deadline := int32(10)
+	tooHigh := int32(101)
+	invalidPause := intstr.FromString("1 minute")
+
+	for _, test := range []struct {
+		name     string
+		mutate   func(ro *Rollout)
+		expected []string
+	}{
+		{name: "valid", mutate: func(ro *Rollout) {}},
+		{name: "workloadRef without selector", mutate: func(ro *Rollout) {
+			ro.Spec.Selector = nil
+			ro.Spec.WorkloadRef = &ObjectRef{Kind: "Deployment", Name: "web"}
+		}},
+		{name: "negative fields", mutate: func(ro *Rollout) {
+			ro.Spec.Replicas = &negative
+			ro.Spec.RevisionHistoryLimit = &negative
+		}, expected: []string{"spec.replicas", "spec.revisionHistoryLimit"}},
+		{name: "progress deadline", mutate: func(ro *Rollout) {
+			ro.Spec.MinReadySeconds = 10
+			ro.Spec.ProgressDeadlineSeconds = &deadline
+		}, expected: []string{"spec.progressDeadlineSeconds"}},
+		{name: "missing selector", mutate: func(ro *Rollout) { ro.Spec.Selector = nil }, expected: []string{"spec.selector"}},
+		{name: "empty selector", mutate: func(ro *Rollout) { ro.Spec.Selector = &metav1.LabelSelector{} }, expected: []string{"spec.selector"}},
+		{name: "selector not matching", mutate: func(ro *Rollout) { ro.Spec.Template.Labels["app"] = "api" },
+			expected: []string{"spec.template.metadata.labels"}},
+		{name: "invalid steps", mutate: func(ro *Rollout) {
+			steps := ro.Spec.Strategy.Canary.Steps
+			steps[0].SetWeight = &tooHigh
+			steps[1].Pause.Duration = &invalidPause
+			steps[2].SetCanaryScale.Replicas = &negative
+			steps[2].SetWeight = &tooHigh
+		}, expected: []string{
+			"spec.strategy.canary.steps[0].setWeight",
+			"spec.strategy.canary.steps[1].pause.duration",
+			"spec.strategy.canary.steps[2].setWeight",
+			"spec.strategy.canary.steps[2].setCanaryScale.replicas",
+			"spec.strategy.canary.steps[2]",
+		}},
+		{name: "empty step", mutate: func(ro *Rollout) {
+			ro.Spec.Strategy.Canary.Steps = append(ro.Spec.Strategy.Canary.Steps, CanaryStep{})
+		}, expected: []string{"spec.strategy.canary.steps[3]"}},
+		{name: "ephemeral metadata and restartAt", mutate: func(ro *Rollout) {
+			ro.Spec.Strategy.Canary.CanaryMetadata = &PodTemplateMetadata{Labels: map[string]string{"app": "canary"}}
+			ro.Spec.RestartAt = &metav1.Time{Time: now.Add(time.Hour)}
+		}, expected: []string{"spec.strategy.canary.canaryMetadata.labels[app]", "spec.restartAt"}},
+	} {
+		ro := newValidRollout()
+		test.mutate(ro)
+		errs := ValidateRollout(ro, now, DefaultRestartAtMaxClockSkew)
+		if len(errs) != len(test.expected) {
+			t.Errorf("%s: expected errors on %v, got %v", test.name, test.expected, errs)
+			continue
+		}
+		for i, err := range errs {
+			if err.Field != test.expected[i] {
+				t.Errorf("%s: expected an error on %s, got %v", test.name, test.expected[i], err)
+			}
+		}
+	}
+}
+
+func TestValidateRolloutUpdate(t *testing.T) {
+	now := time.Date(2022, 11, 21, 10, 0, 0, 0, time.UTC)
+	old := newValidRollout()
+	old.Spec.RestartAt = &metav1.Time{Time: now.Add(time.Hour)}
+
+	ro := old.DeepCopy()
+	ro.Spec.Paused = true
+	if errs := ValidateRolloutUpdate(ro, old, now, DefaultRestartAtMaxClockSkew); len(errs) != 0 {
+		t.Errorf("an unchanged restartAt should not be validated, got %v", errs)
+	}
+
+	ro.Spec.RestartAt = &metav1.Time{Time: now.Add(2 * time.Hour)}
+	ro.Spec.Selector.MatchLabels["tier"] = "front"
+	ro.Spec.Template.Labels["tier"] = "front"
+	errs := ValidateRolloutUpdate(ro, old, now, DefaultRestartAtMaxClockSkew)
+	if len(errs) != 2 || errs[0].Field != "spec.restartAt" || errs[1].Field != "spec.selector" {
+		t.Errorf("expected errors on the restartAt and the immutable selector, got %v", errs)
+	}
+}
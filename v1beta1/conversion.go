@@ -0,0 +1,295 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+// ConversionDataAnnotation holds the v1alpha1 fields that v1beta1 has no equivalent for, so that converting a
+// v1alpha1 Rollout to v1beta1 and back is lossless.
+const ConversionDataAnnotation = "rollout.argoproj.io/conversion-data"
+
+// Hub is the version the other versions convert to and from, it matches the Hub interface of
+// sigs.k8s.io/controller-runtime/pkg/conversion.
+type Hub interface {
+	runtime.Object
+	Hub()
+}
+
+// Convertible is a version that converts to and from the Hub, it matches the Convertible interface of
+// sigs.k8s.io/controller-runtime/pkg/conversion.
+type Convertible interface {
+	runtime.Object
+	ConvertTo(dst Hub) error
+	ConvertFrom(src Hub) error
+}
+
+var _ Hub = &v1alpha1.Rollout{}
+var _ Convertible = &Rollout{}
+
+// conversionData are the v1alpha1 fields stored in the ConversionDataAnnotation
+type conversionData struct {
+	TemplateResolvedFromRef bool `json:"templateResolvedFromRef,omitempty"`
+	SelectorResolvedFromRef bool `json:"selectorResolvedFromRef,omitempty"`
+}
+
+// ConvertTo converts the rollout to the v1alpha1 hub.
+func (src *Rollout) ConvertTo(dstRaw Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Rollout)
+	if !ok {
+		return fmt.Errorf("unsupported hub type %T", dstRaw)
+	}
+	in := src.DeepCopy()
+	dst.TypeMeta = in.TypeMeta
+	if dst.APIVersion != "" {
+		dst.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	}
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = convertSpecToV1alpha1(&in.Spec)
+	dst.Status = convertStatusToV1alpha1(&in.Status)
+
+	if data, ok := dst.Annotations[ConversionDataAnnotation]; ok {
+		var restored conversionData
+		if err := json.Unmarshal([]byte(data), &restored); err != nil {
+			return fmt.Errorf("invalid %s annotation: %w", ConversionDataAnnotation, err)
+		}
+		dst.Spec.TemplateResolvedFromRef = restored.TemplateResolvedFromRef
+		dst.Spec.SelectorResolvedFromRef = restored.SelectorResolvedFromRef
+		delete(dst.Annotations, ConversionDataAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub to this version.
+func (dst *Rollout) ConvertFrom(srcRaw Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Rollout)
+	if !ok {
+		return fmt.Errorf("unsupported hub type %T", srcRaw)
+	}
+	in := src.DeepCopy()
+	dst.TypeMeta = in.TypeMeta
+	if dst.APIVersion != "" {
+		dst.APIVersion = SchemeGroupVersion.String()
+	}
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = convertSpecFromV1alpha1(&in.Spec)
+	dst.Status = convertStatusFromV1alpha1(&in.Status)
+
+	if in.Spec.TemplateResolvedFromRef || in.Spec.SelectorResolvedFromRef {
+		data, err := json.Marshal(conversionData{
+			TemplateResolvedFromRef: in.Spec.TemplateResolvedFromRef,
+			SelectorResolvedFromRef: in.Spec.SelectorResolvedFromRef,
+		})
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[ConversionDataAnnotation] = string(data)
+	}
+	return nil
+}
+
+// The conversion functions below take ownership of their input, the callers pass deep copies.
+
+func convertSpecToV1alpha1(in *RolloutSpec) v1alpha1.RolloutSpec {
+	out := v1alpha1.RolloutSpec{
+		Replicas:                in.Replicas,
+		Selector:                in.Selector,
+		Template:                in.Template,
+		MinReadySeconds:         in.MinReadySeconds,
+		RevisionHistoryLimit:    in.RevisionHistoryLimit,
+		Paused:                  in.Paused,
+		ProgressDeadlineSeconds: in.ProgressDeadlineSeconds,
+		ProgressDeadlineAbort:   in.ProgressDeadlineAbort,
+		RestartAt:               in.RestartAt,
+	}
+	if in.WorkloadRef != nil {
+		ref := v1alpha1.ObjectRef(*in.WorkloadRef)
+		out.WorkloadRef = &ref
+	}
+	if in.Analysis != nil {
+		analysis := v1alpha1.AnalysisRunStrategy(*in.Analysis)
+		out.Analysis = &analysis
+	}
+	if canary := in.Strategy.Canary; canary != nil {
+		out.Strategy.Canary = &v1alpha1.CanaryStrategy{
+			MaxUnavailable: canary.MaxUnavailable,
+			MaxSurge:       canary.MaxSurge,
+		}
+		if canary.Steps != nil {
+			out.Strategy.Canary.Steps = make([]v1alpha1.CanaryStep, len(canary.Steps))
+			for i, step := range canary.Steps {
+				out.Strategy.Canary.Steps[i] = v1alpha1.CanaryStep{SetWeight: step.SetWeight}
+				if step.Pause != nil {
+					pause := v1alpha1.RolloutPause(*step.Pause)
+					out.Strategy.Canary.Steps[i].Pause = &pause
+				}
+				if step.SetCanaryScale != nil {
+					scale := v1alpha1.SetCanaryScale(*step.SetCanaryScale)
+					out.Strategy.Canary.Steps[i].SetCanaryScale = &scale
+				}
+			}
+		}
+		if canary.CanaryMetadata != nil {
+			md := v1alpha1.PodTemplateMetadata(*canary.CanaryMetadata)
+			out.Strategy.Canary.CanaryMetadata = &md
+		}
+		if canary.StableMetadata != nil {
+			md := v1alpha1.PodTemplateMetadata(*canary.StableMetadata)
+			out.Strategy.Canary.StableMetadata = &md
+		}
+	}
+	return out
+}
+
+func convertSpecFromV1alpha1(in *v1alpha1.RolloutSpec) RolloutSpec {
+	out := RolloutSpec{
+		Replicas:                in.Replicas,
+		Selector:                in.Selector,
+		Template:                in.Template,
+		MinReadySeconds:         in.MinReadySeconds,
+		RevisionHistoryLimit:    in.RevisionHistoryLimit,
+		Paused:                  in.Paused,
+		ProgressDeadlineSeconds: in.ProgressDeadlineSeconds,
+		ProgressDeadlineAbort:   in.ProgressDeadlineAbort,
+		RestartAt:               in.RestartAt,
+	}
+	if in.WorkloadRef != nil {
+		ref := ObjectRef(*in.WorkloadRef)
+		out.WorkloadRef = &ref
+	}
+	if in.Analysis != nil {
+		analysis := AnalysisRunStrategy(*in.Analysis)
+		out.Analysis = &analysis
+	}
+	if canary := in.Strategy.Canary; canary != nil {
+		out.Strategy.Canary = &CanaryStrategy{
+			MaxUnavailable: canary.MaxUnavailable,
+			MaxSurge:       canary.MaxSurge,
+		}
+		if canary.Steps != nil {
+			out.Strategy.Canary.Steps = make([]CanaryStep, len(canary.Steps))
+			for i, step := range canary.Steps {
+				out.Strategy.Canary.Steps[i] = CanaryStep{SetWeight: step.SetWeight}
+				if step.Pause != nil {
+					pause := RolloutPause(*step.Pause)
+					out.Strategy.Canary.Steps[i].Pause = &pause
+				}
+				if step.SetCanaryScale != nil {
+					scale := SetCanaryScale(*step.SetCanaryScale)
+					out.Strategy.Canary.Steps[i].SetCanaryScale = &scale
+				}
+			}
+		}
+		if canary.CanaryMetadata != nil {
+			md := PodTemplateMetadata(*canary.CanaryMetadata)
+			out.Strategy.Canary.CanaryMetadata = &md
+		}
+		if canary.StableMetadata != nil {
+			md := PodTemplateMetadata(*canary.StableMetadata)
+			out.Strategy.Canary.StableMetadata = &md
+		}
+	}
+	return out
+}
+
+func convertStatusToV1alpha1(in *RolloutStatus) v1alpha1.RolloutStatus {
+	out := v1alpha1.RolloutStatus{
+		Abort:              in.Abort,
+		ControllerPause:    in.ControllerPause,
+		AbortedAt:          in.AbortedAt,
+		CurrentPodHash:     in.CurrentPodHash,
+		CurrentStepHash:    in.CurrentStepHash,
+		Replicas:           in.Replicas,
+		UpdatedReplicas:    in.UpdatedReplicas,
+		ReadyReplicas:      in.ReadyReplicas,
+		AvailableReplicas:  in.AvailableReplicas,
+		CurrentStepIndex:   in.CurrentStepIndex,
+		CollisionCount:     in.CollisionCount,
+		ObservedGeneration: in.ObservedGeneration,
+		HPAReplicas:        in.HPAReplicas,
+		Selector:           in.Selector,
+		StableRS:           in.StableRS,
+		RestartedAt:        in.RestartedAt,
+		Phase:              v1alpha1.RolloutPhase(in.Phase),
+		Message:            in.Message,
+	}
+	if in.PauseConditions != nil {
+		out.PauseConditions = make([]v1alpha1.PauseCondition, len(in.PauseConditions))
+		for i, cond := range in.PauseConditions {
+			out.PauseConditions[i] = v1alpha1.PauseCondition{
+				Reason:    v1alpha1.PauseReason(cond.Reason),
+				StartTime: cond.StartTime,
+			}
+		}
+	}
+	if in.Conditions != nil {
+		out.Conditions = make([]v1alpha1.RolloutCondition, len(in.Conditions))
+		for i, cond := range in.Conditions {
+			out.Conditions[i] = v1alpha1.RolloutCondition{
+				Type:               v1alpha1.RolloutConditionType(cond.Type),
+				Status:             cond.Status,
+				LastUpdateTime:     cond.LastUpdateTime,
+				LastTransitionTime: cond.LastTransitionTime,
+				Reason:             cond.Reason,
+				Message:            cond.Message,
+			}
+		}
+	}
+	return out
+}
+
+func convertStatusFromV1alpha1(in *v1alpha1.RolloutStatus) RolloutStatus {
+	out := RolloutStatus{
+		Abort:              in.Abort,
+		ControllerPause:    in.ControllerPause,
+		AbortedAt:          in.AbortedAt,
+		CurrentPodHash:     in.CurrentPodHash,
+		CurrentStepHash:    in.CurrentStepHash,
+		Replicas:           in.Replicas,
+		UpdatedReplicas:    in.UpdatedReplicas,
+		ReadyReplicas:      in.ReadyReplicas,
+		AvailableReplicas:  in.AvailableReplicas,
+		CurrentStepIndex:   in.CurrentStepIndex,
+		CollisionCount:     in.CollisionCount,
+		ObservedGeneration: in.ObservedGeneration,
+		HPAReplicas:        in.HPAReplicas,
+		Selector:           in.Selector,
+		StableRS:           in.StableRS,
+		RestartedAt:        in.RestartedAt,
+		Phase:              RolloutPhase(in.Phase),
+		Message:            in.Message,
+	}
+	if in.PauseConditions != nil {
+		out.PauseConditions = make([]PauseCondition, len(in.PauseConditions))
+		for i, cond := range in.PauseConditions {
+			out.PauseConditions[i] = PauseCondition{
+				Reason:    PauseReason(cond.Reason),
+				StartTime: cond.StartTime,
+			}
+		}
+	}
+	if in.Conditions != nil {
+		out.Conditions = make([]RolloutCondition, len(in.Conditions))
+		for i, cond := range in.Conditions {
+			out.Conditions[i] = RolloutCondition{
+				Type:               RolloutConditionType(cond.Type),
+				Status:             cond.Status,
+				LastUpdateTime:     cond.LastUpdateTime,
+				LastTransitionTime: cond.LastTransitionTime,
+				Reason:             cond.Reason,
+				Message:            cond.Message,
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/diff"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+const fuzzIterations = 200
+
+func newFuzzer(t *testing.T) *fuzz.Fuzzer {
+	seed := time.Now().UnixNano()
+	t.Logf("fuzz seed %d", seed)
+	return fuzz.New().NilChance(0.2).NumElements(0, 3).RandSource(rand.NewSource(seed)).Funcs(
+		func(ro *v1alpha1.Rollout, c fuzz.Continue) {
+			c.FuzzNoCustom(ro)
+			ro.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: v1alpha1.RolloutKind}
+			delete(ro.Annotations, ConversionDataAnnotation)
+		},
+		func(ro *Rollout, c fuzz.Continue) {
+			c.FuzzNoCustom(ro)
+			ro.TypeMeta = metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: RolloutKind}
+			delete(ro.Annotations, ConversionDataAnnotation)
+		},
+	)
+}
+
+func TestFuzzRoundTripFromHub(t *testing.T) {
+	f := newFuzzer(t)
+	for i := 0; i < fuzzIterations; i++ {
+		original := &v1alpha1.Rollout{}
+		f.Fuzz(original)
+		input := original.DeepCopy()
+
+		spoke := &Rollout{}
+		if err := spoke.ConvertFrom(input); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		roundTrip := &v1alpha1.Rollout{}
+		if err := spoke.ConvertTo(roundTrip); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(original, input) {
+			t.Fatalf("ConvertFrom() modified its input: %s", diff.ObjectReflectDiff(original, input))
+		}
+		if !equality.Semantic.DeepEqual(original, roundTrip) {
+			t.Fatalf("v1alpha1 -> v1beta1 -> v1alpha1 is lossy: %s", diff.ObjectReflectDiff(original, roundTrip))
+		}
+	}
+}
+
+func TestFuzzRoundTripFromSpoke(t *testing.T) {
+	f := newFuzzer(t)
+	for i := 0; i < fuzzIterations; i++ {
+		original := &Rollout{}
+		f.Fuzz(original)
+		input := original.DeepCopy()
+
+		hub := &v1alpha1.Rollout{}
+		if err := input.ConvertTo(hub); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		roundTrip := &Rollout{}
+		if err := roundTrip.ConvertFrom(hub); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(original, input) {
+			t.Fatalf("ConvertTo() modified its input: %s", diff.ObjectReflectDiff(original, input))
+		}
+		if !equality.Semantic.DeepEqual(original, roundTrip) {
+			t.Fatalf("v1beta1 -> v1alpha1 -> v1beta1 is lossy: %s", diff.ObjectReflectDiff(original, roundTrip))
+		}
+	}
+}
+
+func TestConvertInternalFields(t *testing.T) {
+	hub := &v1alpha1.Rollout{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: v1alpha1.RolloutKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: v1alpha1.RolloutSpec{
+			TemplateResolvedFromRef: true,
+			WorkloadRef:             &v1alpha1.ObjectRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+		},
+		Status: v1alpha1.RolloutStatus{HPAReplicas: 3},
+	}
+	spoke := &Rollout{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if spoke.APIVersion != "argoproj.io/v1beta1" {
+		t.Errorf("APIVersion = %s, want argoproj.io/v1beta1", spoke.APIVersion)
+	}
+	if got := spoke.Annotations[ConversionDataAnnotation]; got != `{"templateResolvedFromRef":true}` {
+		t.Errorf("%s annotation = %q", ConversionDataAnnotation, got)
+	}
+	data, err := json.Marshal(spoke)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"hpaReplicas":3`) || strings.Contains(string(data), `"canary":{}`) {
+		t.Errorf("unexpected v1beta1 json %s", data)
+	}
+
+	roundTrip := &v1alpha1.Rollout{}
+	if err := spoke.ConvertTo(roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if !roundTrip.Spec.TemplateResolvedFromRef || roundTrip.Spec.SelectorResolvedFromRef || roundTrip.Annotations != nil {
+		t.Errorf("unexpected round trip %+v", roundTrip)
+	}
+
+	spoke.Annotations[ConversionDataAnnotation] = "{"
+	if err := spoke.ConvertTo(&v1alpha1.Rollout{}); err == nil {
+		t.Errorf("expected an error for an invalid %s annotation", ConversionDataAnnotation)
+	}
+}
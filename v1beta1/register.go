@@ -0,0 +1,52 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName is the group name of the Rollout resource
+	GroupName = "argoproj.io"
+	// RolloutKind is the kind of the Rollout resource
+	RolloutKind = "Rollout"
+	// RolloutPlural is the plural name of the Rollout resource
+	RolloutPlural = "rollouts"
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1beta1"}
+	// RolloutGVR is the GroupVersionResource of the Rollout resource
+	RolloutGVR = SchemeGroupVersion.WithResource(RolloutPlural)
+	// RolloutGVK is the GroupVersionKind of the Rollout resource
+	RolloutGVK = SchemeGroupVersion.WithKind(RolloutKind)
+)
+
+var (
+	// SchemeBuilder collects the functions adding these types to a scheme
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds these types to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+// Kind takes an unqualified kind and returns back a Group qualified GroupKind
+func Kind(kind string) schema.GroupKind {
+	return SchemeGroupVersion.WithKind(kind).GroupKind()
+}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+// addKnownTypes adds the list of known types to the given scheme.
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&Rollout{},
+		&RolloutList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
@@ -0,0 +1,306 @@
+// Package v1beta1 is the v1beta1 version of the Rollout API. It drops the fields of v1alpha1 that are unused
+// or internal to the controller, and converts to and from v1alpha1, the hub version.
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:path=rollouts,shortName=ro
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.hpaReplicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".spec.replicas",description="Number of desired pods"
+// +kubebuilder:printcolumn:name="Current",type="integer",JSONPath=".status.replicas",description="Total number of non-terminated pods targeted by this rollout"
+// +kubebuilder:printcolumn:name="Up-to-date",type="integer",JSONPath=".status.updatedReplicas",description="Total number of non-terminated pods targeted by this rollout that have the desired template spec"
+// +kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.availableReplicas",description="Total number of available pods (ready for at least minReadySeconds) targeted by this rollout"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time since resource was created"
+// +kubebuilder:subresource:status
+
+// Rollout is a specification for a Rollout resource
+type Rollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec   RolloutSpec   `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+	Status RolloutStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// RolloutSpec is the spec for a Rollout resource
+type RolloutSpec struct {
+	// Number of desired pods. This is a pointer to distinguish between explicit
+	// zero and not specified. Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty" protobuf:"varint,1,opt,name=replicas"`
+	// Label selector for pods. Existing ReplicaSets whose pods are
+	// selected by this will be the ones affected by this rollout.
+	// It must match the pod template's labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty" protobuf:"bytes,2,opt,name=selector"`
+	// Template describes the pods that will be created.
+	// +optional
+	Template corev1.PodTemplateSpec `json:"template,omitempty" protobuf:"bytes,3,opt,name=template"`
+	// WorkloadRef holds a references to a workload that provides Pod template
+	// +optional
+	WorkloadRef *ObjectRef `json:"workloadRef,omitempty" protobuf:"bytes,10,opt,name=workloadRef"`
+	// Minimum number of seconds for which a newly created pod should be ready
+	// without any of its container crashing, for it to be considered available.
+	// Defaults to 0 (pod will be considered available as soon as it is ready)
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty" protobuf:"varint,4,opt,name=minReadySeconds"`
+	// The deployment strategy to use to replace existing pods with new ones.
+	// +optional
+	Strategy RolloutStrategy `json:"strategy" protobuf:"bytes,5,opt,name=strategy"`
+	// The number of old ReplicaSets to retain. If unspecified, will retain 10 old ReplicaSets
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty" protobuf:"varint,6,opt,name=revisionHistoryLimit"`
+	// Paused pauses the rollout at its current step.
+	Paused bool `json:"paused,omitempty" protobuf:"varint,7,opt,name=paused"`
+	// ProgressDeadlineSeconds The maximum time in seconds for a rollout to
+	// make progress before it is considered to be failed. Time spent paused is not counted.
+	// Defaults to 600s.
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty" protobuf:"varint,8,opt,name=progressDeadlineSeconds"`
+	// ProgressDeadlineAbort is whether to abort the update when ProgressDeadlineSeconds
+	// is exceeded.
+	// +optional
+	ProgressDeadlineAbort bool `json:"progressDeadlineAbort,omitempty" protobuf:"varint,12,opt,name=progressDeadlineAbort"`
+	// RestartAt indicates when all the pods of a Rollout should be restarted. Pods created before this time
+	// are restarted.
+	// +optional
+	RestartAt *metav1.Time `json:"restartAt,omitempty" protobuf:"bytes,9,opt,name=restartAt"`
+	// Analysis configuration for the analysis runs and experiments to retain
+	// +optional
+	Analysis *AnalysisRunStrategy `json:"analysis,omitempty" protobuf:"bytes,11,opt,name=analysis"`
+}
+
+// ObjectRef holds a references to the Kubernetes object
+type ObjectRef struct {
+	// API Version of the referent
+	APIVersion string `json:"apiVersion,omitempty" protobuf:"bytes,1,opt,name=apiVersion"`
+	// Kind of the referent
+	Kind string `json:"kind,omitempty" protobuf:"bytes,2,opt,name=kind"`
+	// Name of the referent
+	Name string `json:"name,omitempty" protobuf:"bytes,3,opt,name=name"`
+}
+
+// RolloutStrategy defines strategy to apply during next rollout
+type RolloutStrategy struct {
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty" protobuf:"bytes,2,opt,name=canary"`
+}
+
+// CanaryStrategy defines parameters for a Replica Based Canary
+type CanaryStrategy struct {
+	// Steps define the order of phases to execute the canary deployment
+	// +optional
+	Steps []CanaryStep `json:"steps,omitempty" protobuf:"bytes,3,rep,name=steps"`
+	// MaxUnavailable The maximum number of pods that can be unavailable during the update.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty" protobuf:"bytes,5,opt,name=maxUnavailable"`
+	// MaxSurge The maximum number of pods that can be scheduled above the original number of pods.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty" protobuf:"bytes,6,opt,name=maxSurge"`
+	// CanaryMetadata specify labels and annotations which will be attached to the canary pods for
+	// the duration which they act as a canary, and will be removed after
+	// +optional
+	CanaryMetadata *PodTemplateMetadata `json:"canaryMetadata,omitempty" protobuf:"bytes,9,opt,name=canaryMetadata"`
+	// StableMetadata specify labels and annotations which will be attached to the stable pods for
+	// the duration which they act as a canary, and will be removed after
+	// +optional
+	StableMetadata *PodTemplateMetadata `json:"stableMetadata,omitempty" protobuf:"bytes,10,opt,name=stableMetadata"`
+}
+
+// CanaryStep defines a step of a canary deployment.
+type CanaryStep struct {
+	// SetWeight sets the percentage of the replicas of the canary
+	// +optional
+	SetWeight *int32 `json:"setWeight,omitempty" protobuf:"varint,1,opt,name=setWeight"`
+	// Pause freezes the rollout by setting spec.Paused to true.
+	// A Rollout will resume when spec.Paused is reset to false.
+	// +optional
+	Pause *RolloutPause `json:"pause,omitempty" protobuf:"bytes,2,opt,name=pause"`
+	// SetCanaryScale defines how to scale the newRS without changing traffic weight
+	// +optional
+	SetCanaryScale *SetCanaryScale `json:"setCanaryScale,omitempty" protobuf:"bytes,5,opt,name=setCanaryScale"`
+}
+
+// SetCanaryScale defines how to scale the newRS without changing traffic weight
+type SetCanaryScale struct {
+	// Weight sets the percentage of replicas the newRS should have
+	// +optional
+	Weight *int32 `json:"weight,omitempty" protobuf:"varint,1,opt,name=weight"`
+	// Replicas sets the number of replicas the newRS should have
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty" protobuf:"varint,2,opt,name=replicas"`
+}
+
+// RolloutPause defines a pause stage for a rollout
+type RolloutPause struct {
+	// Duration the amount of time to wait before moving to the next step.
+	// +optional
+	Duration *intstr.IntOrString `json:"duration,omitempty" protobuf:"bytes,1,opt,name=duration"`
+}
+
+// PodTemplateMetadata extra labels to add to the template
+type PodTemplateMetadata struct {
+	// Labels Additional labels to add to the pods
+	// +optional
+	Labels map[string]string `json:"labels,omitempty" protobuf:"bytes,1,rep,name=labels"`
+	// Annotations additional annotations to add to the pods
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,2,rep,name=annotations"`
+}
+
+// AnalysisRunStrategy configuration for the analysis runs and experiments to retain
+type AnalysisRunStrategy struct {
+	// SuccessfulRunHistoryLimit limits the number of old successful analysis runs and experiments to be retained in a history
+	SuccessfulRunHistoryLimit *int32 `json:"successfulRunHistoryLimit,omitempty" protobuf:"varint,1,opt,name=successfulRunHistoryLimit"`
+	// UnsuccessfulRunHistoryLimit limits the number of old unsuccessful analysis runs and experiments to be retained in a history.
+	// Stages for unsuccessful: "Error", "Failed", "Inconclusive"
+	UnsuccessfulRunHistoryLimit *int32 `json:"unsuccessfulRunHistoryLimit,omitempty" protobuf:"varint,2,opt,name=unsuccessfulRunHistoryLimit"`
+}
+
+// PauseReason reasons that the rollout can pause
+type PauseReason string
+
+const (
+	// PauseReasonInconclusiveAnalysis pauses rollout when rollout has an inconclusive analysis run
+	PauseReasonInconclusiveAnalysis PauseReason = "InconclusiveAnalysisRun"
+	// PauseReasonCanaryPauseStep pause rollout for canary pause step
+	PauseReasonCanaryPauseStep PauseReason = "CanaryPauseStep"
+)
+
+// PauseCondition the reason for a pause and when it started
+type PauseCondition struct {
+	Reason    PauseReason `json:"reason" protobuf:"bytes,1,opt,name=reason,casttype=PauseReason"`
+	StartTime metav1.Time `json:"startTime" protobuf:"bytes,2,opt,name=startTime"`
+}
+
+// RolloutPhase are a set of phases that this rollout
+type RolloutPhase string
+
+const (
+	// RolloutPhaseHealthy indicates a rollout is healthy
+	RolloutPhaseHealthy RolloutPhase = "Healthy"
+	// RolloutPhaseDegraded indicates a rollout is degraded (e.g. pod unavailability, misconfiguration)
+	RolloutPhaseDegraded RolloutPhase = "Degraded"
+	// RolloutPhaseProgressing indicates a rollout is not yet healthy but still making progress towards a healthy state
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhasePaused indicates a rollout is not yet healthy and will not make progress until unpaused
+	RolloutPhasePaused RolloutPhase = "Paused"
+)
+
+// RolloutStatus is the status for a Rollout resource
+type RolloutStatus struct {
+	// Abort is true when the rollout was aborted by a user, it scales back to the stable ReplicaSet
+	Abort bool `json:"abort,omitempty" protobuf:"varint,1,opt,name=abort"`
+	// PauseConditions are the reasons the controller paused the rollout, for example CanaryPauseStep
+	PauseConditions []PauseCondition `json:"pauseConditions,omitempty" protobuf:"bytes,2,rep,name=pauseConditions"`
+	// ControllerPause is true when the controller paused the rollout. It stays true when a user resumes the
+	// rollout and the PauseConditions are cleared.
+	ControllerPause bool `json:"controllerPause,omitempty" protobuf:"varint,3,opt,name=controllerPause"`
+	// AbortedAt is the time the rollout was aborted
+	// +optional
+	AbortedAt *metav1.Time `json:"abortedAt,omitempty" protobuf:"bytes,4,opt,name=abortedAt"`
+	// CurrentPodHash is the pod template hash of the current pod template
+	// +optional
+	CurrentPodHash string `json:"currentPodHash,omitempty" protobuf:"bytes,5,opt,name=currentPodHash"`
+	// CurrentStepHash is the hash of the list of canary steps, used to detect changes of the steps
+	// +optional
+	CurrentStepHash string `json:"currentStepHash,omitempty" protobuf:"bytes,6,opt,name=currentStepHash"`
+	// Total number of non-terminated pods targeted by this rollout (their labels match the selector).
+	// +optional
+	Replicas int32 `json:"replicas,omitempty" protobuf:"varint,7,opt,name=replicas"`
+	// Total number of non-terminated pods targeted by this rollout that have the desired template spec.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty" protobuf:"varint,8,opt,name=updatedReplicas"`
+	// Total number of ready pods targeted by this rollout.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty" protobuf:"varint,9,opt,name=readyReplicas"`
+	// Total number of available pods (ready for at least minReadySeconds) targeted by this rollout.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty" protobuf:"varint,10,opt,name=availableReplicas"`
+	// CurrentStepIndex is the index of the current canary step, nil when the rollout has not started yet.
+	// +optional
+	CurrentStepIndex *int32 `json:"currentStepIndex,omitempty" protobuf:"varint,11,opt,name=currentStepIndex"`
+	// Count of hash collisions for the Rollout. The controller uses this field as a collision avoidance
+	// mechanism when it needs to create the name for the newest ReplicaSet.
+	// +optional
+	CollisionCount *int32 `json:"collisionCount,omitempty" protobuf:"varint,12,opt,name=collisionCount"`
+	// The generation observed by the rollout controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"bytes,13,opt,name=observedGeneration"`
+	// Conditions a list of conditions a rollout can have.
+	// +optional
+	Conditions []RolloutCondition `json:"conditions,omitempty" protobuf:"bytes,14,rep,name=conditions"`
+	// HPAReplicas the number of non-terminated replicas that are receiving active traffic
+	// +optional
+	HPAReplicas int32 `json:"hpaReplicas,omitempty" protobuf:"varint,17,opt,name=hpaReplicas"`
+	// Selector that identifies the pods that are receiving active traffic
+	// +optional
+	Selector string `json:"selector,omitempty" protobuf:"bytes,18,opt,name=selector"`
+	// StableRS is the pod template hash of the stable ReplicaSet
+	// +optional
+	StableRS string `json:"stableRS,omitempty" protobuf:"bytes,19,opt,name=stableRS"`
+	// RestartedAt indicates last time a Rollout was restarted
+	// +optional
+	RestartedAt *metav1.Time `json:"restartedAt,omitempty" protobuf:"bytes,21,opt,name=restartedAt"`
+	// Phase is the rollout phase. Clients should only rely on the value if status.observedGeneration equals
+	// metadata.generation
+	Phase RolloutPhase `json:"phase,omitempty" protobuf:"bytes,22,opt,name=phase,casttype=RolloutPhase"`
+	// Message provides details on why the rollout is in its current phase
+	Message string `json:"message,omitempty" protobuf:"bytes,23,opt,name=message"`
+}
+
+// RolloutConditionType defines the conditions of Rollout
+type RolloutConditionType string
+
+// These are valid conditions of a rollout.
+const (
+	// InvalidSpec means the rollout has an invalid spec and will not progress until the spec is fixed.
+	InvalidSpec RolloutConditionType = "InvalidSpec"
+	// RolloutAvailable means the rollout is available, ie. the replicas are up and running for at least
+	// minReadySeconds.
+	RolloutAvailable RolloutConditionType = "Available"
+	// RolloutProgressing means the rollout is progressing: a new ReplicaSet is created or scaled up, or an
+	// old one is scaled down.
+	RolloutProgressing RolloutConditionType = "Progressing"
+	// RolloutReplicaFailure ReplicaFailure is added in a deployment when one of its pods
+	// fails to be created or deleted.
+	RolloutReplicaFailure RolloutConditionType = "ReplicaFailure"
+	// RolloutPaused means the rollout is paused. The time spent paused does not count toward the
+	// progress deadline.
+	RolloutPaused RolloutConditionType = "Paused"
+	// RolloutCompleted means the rollout reached the desired revision and is not in an intermediate state.
+	RolloutCompleted RolloutConditionType = "Completed"
+	// RolloutHealthy means the rollout is completed and all its replicas are available.
+	RolloutHealthy RolloutConditionType = "Healthy"
+)
+
+// RolloutCondition describes the state of a rollout at a certain point.
+type RolloutCondition struct {
+	// Type of deployment condition.
+	Type RolloutConditionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=RolloutConditionType"`
+	// Phase of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status" protobuf:"bytes,2,opt,name=status,casttype=k8s.io/api/core/v1.ConditionStatus"`
+	// The last time this condition was updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime" protobuf:"bytes,3,opt,name=lastUpdateTime"`
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime" protobuf:"bytes,4,opt,name=lastTransitionTime"`
+	// The reason for the condition's last transition.
+	Reason string `json:"reason" protobuf:"bytes,5,opt,name=reason"`
+	// A human readable message indicating details about the transition.
+	Message string `json:"message" protobuf:"bytes,6,opt,name=message"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RolloutList is a list of Rollout resources
+type RolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []Rollout `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
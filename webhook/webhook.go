@@ -0,0 +1,215 @@
+// Package webhook serves the admission webhooks of Rollouts: the mutating webhook sets the default values
+// of a Rollout with a JSON patch and the validating webhook rejects invalid Rollouts with field errors.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+const (
+	// MutatePath is the path of the mutating webhook in the Handler
+	MutatePath = "/mutate-rollout"
+	// ValidatePath is the path of the validating webhook in the Handler
+	ValidatePath = "/validate-rollout"
+
+	// maxRequestBytes is the maximum size of an AdmissionReview: the API server limits requests to 3MB and
+	// the review of an update holds both the object and the old object
+	maxRequestBytes = 7 * 1024 * 1024
+)
+
+// Webhook handles admission/v1 AdmissionReviews of Rollouts. The zero value is ready to use.
+type Webhook struct {
+	// MaxClockSkew is the tolerated clock skew when validating Spec.RestartAt,
+	// v1alpha1.DefaultRestartAtMaxClockSkew when 0
+	MaxClockSkew time.Duration
+	// Now returns the current time, time.Now when nil
+	Now func() time.Time
+}
+
+// Handler returns an http.Handler serving the mutating webhook on MutatePath and the validating webhook
+// on ValidatePath.
+func (w *Webhook) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(MutatePath, w.MutatingHandler())
+	mux.Handle(ValidatePath, w.ValidatingHandler())
+	return mux
+}
+
+// MutatingHandler returns an http.Handler serving the mutating webhook.
+func (w *Webhook) MutatingHandler() http.Handler {
+	return reviewHandler(w.Mutate)
+}
+
+// ValidatingHandler returns an http.Handler serving the validating webhook.
+func (w *Webhook) ValidatingHandler() http.Handler {
+	return reviewHandler(w.Validate)
+}
+
+// Mutate sets the default values of a created or updated Rollout, see v1alpha1.SetRolloutDefaults. The
+// response holds a JSON patch from the object of the request when a default is set. Other operations, kinds
+// and versions are allowed unchanged.
+func (w *Webhook) Mutate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if !isRolloutWrite(req) {
+		return allowed(req)
+	}
+	rollout, err := decodeRollout(req.Object.Raw)
+	if err != nil {
+		return errored(req, http.StatusBadRequest, err)
+	}
+	v1alpha1.SetRolloutDefaults(rollout)
+	defaulted, err := json.Marshal(rollout)
+	if err != nil {
+		return errored(req, http.StatusInternalServerError, err)
+	}
+	operations, err := jsonpatch.CreatePatch(req.Object.Raw, defaulted)
+	if err != nil {
+		return errored(req, http.StatusInternalServerError, err)
+	}
+	resp := allowed(req)
+	if len(operations) == 0 {
+		return resp
+	}
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return errored(req, http.StatusInternalServerError, err)
+	}
+	patchType := admissionv1.PatchTypeJSONPatch
+	resp.Patch = patch
+	resp.PatchType = &patchType
+	return resp
+}
+
+// Validate checks a created or updated Rollout with v1alpha1.ValidateRollout or v1alpha1.ValidateRolloutUpdate.
+// A rejected Rollout gets an Invalid status listing the field errors as causes. Other operations, kinds
+// and versions are allowed.
+func (w *Webhook) Validate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if !isRolloutWrite(req) {
+		return allowed(req)
+	}
+	rollout, err := decodeRollout(req.Object.Raw)
+	if err != nil {
+		return errored(req, http.StatusBadRequest, err)
+	}
+	now := time.Now()
+	if w.Now != nil {
+		now = w.Now()
+	}
+	maxClockSkew := w.MaxClockSkew
+	if maxClockSkew == 0 {
+		maxClockSkew = v1alpha1.DefaultRestartAtMaxClockSkew
+	}
+
+	var errs field.ErrorList
+	if req.Operation == admissionv1.Update {
+		old, err := decodeRollout(req.OldObject.Raw)
+		if err != nil {
+			return errored(req, http.StatusBadRequest, err)
+		}
+		errs = v1alpha1.ValidateRolloutUpdate(rollout, old, now, maxClockSkew)
+	} else {
+		errs = v1alpha1.ValidateRollout(rollout, now, maxClockSkew)
+	}
+	if len(errs) == 0 {
+		return allowed(req)
+	}
+	name := rollout.Name
+	if name == "" {
+		name = req.Name
+	}
+	status := apierrors.NewInvalid(v1alpha1.Kind(v1alpha1.RolloutKind), name, errs).ErrStatus
+	return &admissionv1.AdmissionResponse{UID: req.UID, Result: &status}
+}
+
+// reviewHandler decodes the AdmissionReview of the request and writes back the review with the
+// response of admit.
+func reviewHandler(admit func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(rw, fmt.Sprintf("method %s is not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes+1))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(body) > maxRequestBytes {
+			http.Error(rw, fmt.Sprintf("the admission review exceeds %d bytes", maxRequestBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		review := &admissionv1.AdmissionReview{}
+		if err := json.Unmarshal(body, review); err != nil {
+			http.Error(rw, fmt.Sprintf("unable to decode the admission review: %v", err), http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			http.Error(rw, "the admission review has no request", http.StatusBadRequest)
+			return
+		}
+		review.Response = admit(review.Request)
+		review.Request = nil
+		review.SetGroupVersionKind(admissionv1.SchemeGroupVersion.WithKind("AdmissionReview"))
+
+		data, err := json.Marshal(review)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write(data)
+	})
+}
+
+// isRolloutWrite returns true for the creation or update of a v1alpha1 Rollout. Requests for other versions
+// are not decoded as v1alpha1: the webhooks should be registered for v1alpha1 with the Equivalent match
+// policy, so that the API server converts them.
+func isRolloutWrite(req *admissionv1.AdmissionRequest) bool {
+	if req.Kind.Group != v1alpha1.GroupName || req.Kind.Version != v1alpha1.SchemeGroupVersion.Version ||
+		req.Kind.Kind != v1alpha1.RolloutKind {
+		return false
+	}
+	return req.SubResource == "" && (req.Operation == admissionv1.Create || req.Operation == admissionv1.Update)
+}
+
+func decodeRollout(raw []byte) (*v1alpha1.Rollout, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("the admission request has no object")
+	}
+	rollout := &v1alpha1.Rollout{}
+	if err := json.Unmarshal(raw, rollout); err != nil {
+		return nil, fmt.Errorf("unable to decode the rollout: %w", err)
+	}
+	return rollout, nil
+}
+
+func allowed(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{UID: req.UID, Allowed: true}
+}
+
+func errored(req *admissionv1.AdmissionRequest, code int32, err error) *admissionv1.AdmissionResponse {
+	reason := metav1.StatusReasonBadRequest
+	if code == http.StatusInternalServerError {
+		reason = metav1.StatusReasonInternalError
+	}
+	return &admissionv1.AdmissionResponse{
+		UID: req.UID,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    code,
+			Reason:  reason,
+			Message: err.Error(),
+		},
+	}
+}
@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/chamhaw/kubernetes-rollout-api/v1alpha1"
+)
+
+var now = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newRollout() *v1alpha1.Rollout {
+	weight := int32(20)
+	return &v1alpha1.Rollout{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: v1alpha1.RolloutKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "nginx:1.23"}}},
+			},
+			Strategy: v1alpha1.RolloutStrategy{Canary: &v1alpha1.CanaryStrategy{
+				Steps: []v1alpha1.CanaryStep{{SetWeight: &weight}, {Pause: &v1alpha1.RolloutPause{}}},
+			}},
+		},
+	}
+}
+
+func review(t *testing.T, server *httptest.Server, path string, operation admissionv1.Operation, obj, old *v1alpha1.Rollout) *admissionv1.AdmissionResponse {
+	t.Helper()
+	req := &admissionv1.AdmissionRequest{
+		UID:       types.UID("uid-1"),
+		Kind:      metav1.GroupVersionKind{Group: v1alpha1.GroupName, Version: "v1alpha1", Kind: v1alpha1.RolloutKind},
+		Name:      "web",
+		Namespace: "default",
+		Operation: operation,
+	}
+	if obj != nil {
+		req.Object = runtime.RawExtension{Object: obj}
+	}
+	if old != nil {
+		req.OldObject = runtime.RawExtension{Object: old}
+	}
+	body, err := json.Marshal(&admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+		Request:  req,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(server.URL+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %s", resp.Status)
+	}
+	var result admissionv1.AdmissionReview
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Kind != "AdmissionReview" || result.APIVersion != "admission.k8s.io/v1" || result.Response == nil {
+		t.Fatalf("unexpected admission review %v", result)
+	}
+	if result.Response.UID != req.UID {
+		t.Errorf("expected the response UID %s, got %s", req.UID, result.Response.UID)
+	}
+	return result.Response
+}
+
+func TestMutate(t *testing.T) {
+	server := httptest.NewServer((&Webhook{}).Handler())
+	defer server.Close()
+
+	ro := newRollout()
+	resp := review(t, server, MutatePath, admissionv1.Create, ro, nil)
+	if !resp.Allowed || resp.PatchType == nil || *resp.PatchType != admissionv1.PatchTypeJSONPatch {
+		t.Fatalf("expected an allowed response with a JSON patch, got %v", resp)
+	}
+	expected := ro.DeepCopy()
+	v1alpha1.SetRolloutDefaults(expected)
+	analysis := mustMarshal(t, expected.Spec.Analysis)
+	expectedPatch := fmt.Sprintf(`[{"op":"add","path":"/spec/analysis","value":%s},`+
+		`{"op":"add","path":"/spec/progressDeadlineSeconds","value":%d},{"op":"add","path":"/spec/replicas","value":%d},`+
+		`{"op":"add","path":"/spec/revisionHistoryLimit","value":%d}]`, analysis, *expected.Spec.ProgressDeadlineSeconds,
+		*expected.Spec.Replicas, *expected.Spec.RevisionHistoryLimit)
+	if patch := sortedPatch(t, resp.Patch); patch != expectedPatch {
+		t.Errorf("expected the patch %s, got %s", expectedPatch, patch)
+	}
+
+	resp = review(t, server, MutatePath, admissionv1.Update, expected, ro)
+	if !resp.Allowed || resp.Patch != nil {
+		t.Errorf("expected no patch for a defaulted rollout, got %s", resp.Patch)
+	}
+	resp = review(t, server, MutatePath, admissionv1.Delete, nil, ro)
+	if !resp.Allowed || resp.Patch != nil {
+		t.Errorf("expected a delete to be allowed unchanged, got %v", resp)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	server := httptest.NewServer((&Webhook{Now: func() time.Time { return now }}).Handler())
+	defer server.Close()
+
+	ro := newRollout()
+	if resp := review(t, server, ValidatePath, admissionv1.Create, ro, nil); !resp.Allowed {
+		t.Errorf("expected a valid rollout to be allowed, got %v", resp.Result)
+	}
+
+	invalid := ro.DeepCopy()
+	weight := int32(120)
+	invalid.Spec.Strategy.Canary.Steps[0].SetWeight = &weight
+	invalid.Spec.RestartAt = &metav1.Time{Time: now.Add(time.Hour)}
+	resp := review(t, server, ValidatePath, admissionv1.Create, invalid, nil)
+	if resp.Allowed || resp.Result == nil || resp.Result.Reason != metav1.StatusReasonInvalid || resp.Result.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected an invalid rollout to be rejected, got %v", resp)
+	}
+	var fields []string
+	for _, cause := range resp.Result.Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	if len(fields) != 2 || fields[0] != "spec.strategy.canary.steps[0].setWeight" || fields[1] != "spec.restartAt" {
+		t.Errorf("unexpected causes %v", resp.Result.Details.Causes)
+	}
+
+	// restartAt is only checked when it changes, the selector is immutable
+	old := ro.DeepCopy()
+	old.Spec.RestartAt = invalid.Spec.RestartAt
+	updated := old.DeepCopy()
+	updated.Spec.Selector.MatchLabels["tier"] = "front"
+	updated.Spec.Template.Labels["tier"] = "front"
+	resp = review(t, server, ValidatePath, admissionv1.Update, updated, old)
+	if resp.Allowed || len(resp.Result.Details.Causes) != 1 || resp.Result.Details.Causes[0].Field != "spec.selector" {
+		t.Errorf("expected a selector change to be rejected, got %v", resp.Result)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	server := httptest.NewServer((&Webhook{}).Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + ValidatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected %d for a GET, got %d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+	for _, body := range []string{"not json", `{"kind":"AdmissionReview"}`} {
+		resp, err := http.Post(server.URL+MutatePath, "application/json", bytes.NewReader([]byte(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected %d for %q, got %d", http.StatusBadRequest, body, resp.StatusCode)
+		}
+	}
+
+	// an undecodable rollout is an errored response, not an HTTP error
+	w := &Webhook{}
+	req := &admissionv1.AdmissionRequest{
+		UID:       "uid-2",
+		Kind:      metav1.GroupVersionKind{Group: v1alpha1.GroupName, Version: "v1alpha1", Kind: v1alpha1.RolloutKind},
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: []byte(`{"spec":{"replicas":"three"}}`)},
+	}
+	if result := w.Validate(req); result.Allowed || result.Result.Code != http.StatusBadRequest {
+		t.Errorf("expected a bad request, got %v", result)
+	}
+	req.Kind.Kind = "Deployment"
+	if result := w.Mutate(req); !result.Allowed {
+		t.Errorf("expected other kinds to be allowed, got %v", result)
+	}
+	req.Kind = metav1.GroupVersionKind{Group: v1alpha1.GroupName, Version: "v1beta1", Kind: v1alpha1.RolloutKind}
+	if result := w.Validate(req); !result.Allowed {
+		t.Errorf("expected other versions to be allowed, got %v", result)
+	}
+
+	resp, err = http.Post(server.URL+MutatePath, "application/json", bytes.NewReader(make([]byte, maxRequestBytes+1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected %d for an oversized review, got %d", http.StatusRequestEntityTooLarge, resp.StatusCode)
+	}
+}
+
+func TestMutateRawObject(t *testing.T) {
+	// the patch is computed from the object as sent, which has no spec
+	req := &admissionv1.AdmissionRequest{
+		UID:       "uid-3",
+		Kind:      metav1.GroupVersionKind{Group: v1alpha1.GroupName, Version: "v1alpha1", Kind: v1alpha1.RolloutKind},
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"argoproj.io/v1alpha1","kind":"Rollout","metadata":{"name":"web"}}`)},
+	}
+	resp := (&Webhook{}).Mutate(req)
+	if !resp.Allowed {
+		t.Fatalf("expected an allowed response, got %v", resp.Result)
+	}
+	var operations []jsonpatch.Operation
+	if err := json.Unmarshal(resp.Patch, &operations); err != nil {
+		t.Fatal(err)
+	}
+	addsSpec := false
+	for _, op := range operations {
+		if strings.HasPrefix(op.Path, "/spec/") {
+			t.Errorf("expected the patch to add the spec, got an operation on %s", op.Path)
+		}
+		addsSpec = addsSpec || (op.Operation == "add" && op.Path == "/spec")
+	}
+	if !addsSpec {
+		t.Errorf("expected the patch to add the spec, got %s", resp.Patch)
+	}
+}
+
+// sortedPatch returns the JSON patch with its operations sorted by path.
+func sortedPatch(t *testing.T, patch []byte) string {
+	t.Helper()
+	var operations []jsonpatch.Operation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(operations, func(i, j int) bool { return operations[i].Path < operations[j].Path })
+	return string(mustMarshal(t, operations))
+}
+
+func mustMarshal(t *testing.T, obj interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}